@@ -0,0 +1,412 @@
+package morm
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// BulkOption configures bulk operations like [InsertMany]
+type BulkOption func(*bulk_options)
+
+type bulk_options struct {
+	// called after every chunk with the records done so far and the total
+	progress func(done, total int)
+
+	// max records per statement, the engine limits apply when 0
+	chunksize int
+}
+
+func new_bulk_options(opts []BulkOption) bulk_options {
+	var bo bulk_options
+	for _, opt := range opts {
+		opt(&bo)
+	}
+	return bo
+}
+
+// WithProgress calls fn after every chunk is executed with the number of records done so far and the total
+func WithProgress(fn func(done, total int)) BulkOption {
+	return func(bo *bulk_options) {
+		bo.progress = fn
+	}
+}
+
+// ChunkSize caps the number of records per statement, the engine limits still apply
+func ChunkSize(n int) BulkOption {
+	return func(bo *bulk_options) {
+		bo.chunksize = n
+	}
+}
+
+// bulk_limits returns the max rows per VALUES list and the max parameters per statement of the engine, 0 is no limit
+func bulk_limits(engine ENGINE) (rows, params int) {
+	switch engine {
+	case SQLITE:
+		// note: 999 is the variable limit of sqlite builds before 3.32
+		return 500, 999
+	case SQLServer:
+		return 1000, 2100
+	case MySQL:
+		return 0, 65535
+	default:
+		return 0, 0
+	}
+}
+
+// chunk_size returns the number of records per statement for records of columns columns
+func chunk_size(engine ENGINE, columns int, opts bulk_options) int {
+	rows, params := bulk_limits(engine)
+
+	size := opts.chunksize
+	if rows > 0 && (size <= 0 || size > rows) {
+		size = rows
+	}
+
+	if params > 0 && columns > 0 && (size <= 0 || size > params/columns) {
+		size = params / columns
+	}
+
+	if size <= 0 {
+		size = 1000
+	}
+
+	return size
+}
+
+// InsertMany inserts records in multi-row statements chunked to the engine limits, all in a single transaction.
+//
+// Generated keys are not written back. Records with adjecent tables are inserted one by one like [MORM.Insert],
+// in which case generated keys are written back. If tablename is empty it is derived from T
+func InsertMany[T any](m *MORM, records []T, tablename string, opts ...BulkOption) Result {
+	if m == nil {
+		return error_result(ErrDefaultClientIsNil)
+	}
+
+	t := reflect.TypeFor[T]()
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return error_result(fmt.Errorf("%w: unable to insert %s, expected a struct", ErrUnsupportedType, t))
+	}
+
+	if len(records) == 0 {
+		return new_result(nil, 0)
+	}
+
+	if tablename == "" {
+		tablename = default_tablename(t)
+	}
+
+	bo := new_bulk_options(opts)
+
+	tx, e := begin_tx(m)
+	if e != nil {
+		return error_result(e)
+	}
+
+	affected, e := insert_many(tx, reflect.ValueOf(records), tablename, bo, m)
+	if e != nil {
+		tx.Rollback()
+		return error_result(e)
+	}
+
+	return new_result(tx.Commit(), affected)
+}
+
+func insert_many(tx *sql.Tx, records reflect.Value, tablename string, bo bulk_options, m *MORM) (int64, error) {
+	if e := nil_record(records); e != nil {
+		return 0, e
+	}
+	total := records.Len()
+
+	record := func(i int) reflect.Value {
+		v := records.Index(i)
+		if v.Kind() == reflect.Pointer {
+			v = v.Elem()
+		}
+		return v
+	}
+
+	fields, _, adjecent, e := insertfields(record(0).Interface())
+	if e != nil {
+		return 0, err_wrap(e, "unable to insert record 0")
+	}
+	size := chunk_size(m.engine, len(fields), bo)
+
+	// note: adjecent records need the key of their parent so they are inserted one by one
+	if len(adjecent) > 0 || len(fields) == 0 {
+		for i := 0; i < total; i++ {
+			e := insert_record(tx, record(i), tablename, nil, m)
+			if e != nil {
+				return 0, err_wrap(e, fmt.Sprintf("unable to insert record %d", i))
+			}
+
+			if bo.progress != nil && ((i+1)%size == 0 || i+1 == total) {
+				bo.progress(i+1, total)
+			}
+		}
+		return int64(total), nil
+	}
+
+	var affected int64
+	for start := 0; start < total; start += size {
+		end := min(start+size, total)
+
+		rows := make([]string, 0, end-start)
+		args := make([]any, 0, (end-start)*len(fields))
+		for i := start; i < end; i++ {
+			_, values, _, e := insertfields(record(i).Interface())
+			if e != nil {
+				return 0, err_wrap(e, fmt.Sprintf("unable to insert record %d", i))
+			}
+			rows = append(rows, fmt.Sprintf("(%s)", placeholders(len(values))))
+			args = append(args, values...)
+		}
+
+		query := statement{
+			query: fmt.Sprintf("insert into %s(%s)\nvalues %s", tablename, strings.Join(fields, ", "), strings.Join(rows, ",\n")),
+			args:  args,
+		}
+
+		rslt, e := query.exec(tx, m.engine)
+		if e != nil {
+			return 0, err_wrap(e, fmt.Sprintf("unable to insert records %d to %d", start, end-1))
+		}
+
+		n, e := rslt.RowsAffected()
+		if e != nil {
+			return 0, e
+		}
+		affected += n
+
+		if bo.progress != nil {
+			bo.progress(end, total)
+		}
+	}
+
+	return affected, nil
+}
+
+// UpdateMany updates every record by primary key with its own values in statements chunked to the engine limits, all
+// in a single transaction. The Result has the rows affected of every statement combined.
+//
+// fields are the Go field names or column names to update, every column but the primary key when none. SQLITE and
+// MySQL render an UPDATE ... CASE per chunk and SQL Server an UPDATE ... FROM (VALUES ...)
+func UpdateMany[T any](m *MORM, records []T, tablename string, fields []string, opts ...BulkOption) Result {
+	if m == nil {
+		return error_result(ErrDefaultClientIsNil)
+	}
+
+	t := reflect.TypeFor[T]()
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return error_result(fmt.Errorf("%w: unable to update %s, expected a struct", ErrUnsupportedType, t))
+	}
+
+	pk, ok := primary_key(t)
+	if !ok {
+		return error_result(fmt.Errorf("%w: %s records can not be updated by key", ErrNoPrimaryKey, t.Name()))
+	}
+
+	columns, e := update_columns(t, pk, fields)
+	if e != nil {
+		return error_result(e)
+	}
+
+	if len(columns) == 0 {
+		return error_result(fmt.Errorf("%s has no columns to update", t.Name()))
+	}
+
+	if len(records) == 0 {
+		return new_result(nil, 0)
+	}
+
+	if tablename == "" {
+		tablename = default_tablename(t)
+	}
+
+	bo := new_bulk_options(opts)
+
+	tx, e := begin_tx(m)
+	if e != nil {
+		return error_result(e)
+	}
+
+	affected, e := update_many(tx, reflect.ValueOf(records), tablename, pk, columns, bo, m)
+	if e != nil {
+		tx.Rollback()
+		return error_result(e)
+	}
+
+	return new_result(tx.Commit(), affected)
+}
+
+// update_columns returns the columns of t matching fields by Go field name or column name, every column but the
+// primary key when fields is empty
+func update_columns(t reflect.Type, pk column, fields []string) ([]column, error) {
+	all, e := model_columns(t)
+	if e != nil {
+		return nil, e
+	}
+
+	if len(fields) == 0 {
+		columns := make([]column, 0, len(all))
+		for _, c := range all {
+			if c.name != pk.name {
+				columns = append(columns, c)
+			}
+		}
+		return columns, nil
+	}
+
+	columns := make([]column, 0, len(fields))
+	for _, field := range fields {
+		found := false
+		for _, c := range all {
+			if c.field.Name == field || strings.Trim(c.name, "[]") == field {
+				columns = append(columns, c)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("%w: %s field not found in %s", ErrUnmappedField, field, t.Name())
+		}
+	}
+
+	return columns, nil
+}
+
+func update_many(tx *sql.Tx, records reflect.Value, tablename string, pk column, columns []column, bo bulk_options, m *MORM) (int64, error) {
+	if e := nil_record(records); e != nil {
+		return 0, e
+	}
+	total := records.Len()
+
+	// note: every record uses its key twice in a case update
+	size := chunk_size(m.engine, 2*len(columns)+1, bo)
+
+	var affected int64
+	for start := 0; start < total; start += size {
+		end := min(start+size, total)
+
+		keys := make([]any, 0, end-start)
+		values := make([][]any, 0, end-start)
+		for i := start; i < end; i++ {
+			v := records.Index(i)
+			if v.Kind() == reflect.Pointer {
+				v = v.Elem()
+			}
+
+			key, e := field_arg(v.FieldByIndex(pk.index), pk.field.Type, pk.tag)
+			if e != nil {
+				return 0, err_wrap(e, fmt.Sprintf("unable to bind the key of record %d", i))
+			}
+
+			row := make([]any, len(columns))
+			for j, c := range columns {
+				value, e := field_arg(v.FieldByIndex(c.index), c.field.Type, c.tag)
+				if e != nil {
+					return 0, err_wrap(e, fmt.Sprintf("unable to bind %s of record %d", c.name, i))
+				}
+				row[j] = value
+			}
+
+			keys = append(keys, key)
+			values = append(values, row)
+		}
+
+		var query statement
+		switch m.engine {
+		case SQLServer:
+			query = mssql_update_many_query(tablename, pk, columns, keys, values)
+		default:
+			query = case_update_many_query(tablename, pk, columns, keys, values)
+		}
+
+		rslt, e := query.exec(tx, m.engine)
+		if e != nil {
+			return 0, err_wrap(e, fmt.Sprintf("unable to update records %d to %d", start, end-1))
+		}
+
+		n, e := rslt.RowsAffected()
+		if e != nil {
+			return 0, e
+		}
+		affected += n
+
+		if bo.progress != nil {
+			bo.progress(end, total)
+		}
+	}
+
+	return affected, nil
+}
+
+func case_update_many_query(tablename string, pk column, columns []column, keys []any, values [][]any) statement {
+	var args []any
+	sets := make([]string, len(columns))
+	for j, c := range columns {
+		var b strings.Builder
+		fmt.Fprintf(&b, "%s = case %s", c.name, pk.name)
+		for i, key := range keys {
+			b.WriteString(" when ? then ?")
+			args = append(args, key, values[i][j])
+		}
+		fmt.Fprintf(&b, " else %s end", c.name)
+		sets[j] = b.String()
+	}
+	args = append(args, keys...)
+
+	return statement{
+		query: fmt.Sprintf("update %s\nset %s\nwhere %s in (%s);", tablename, strings.Join(sets, ",\n"), pk.name, placeholders(len(keys))),
+		args:  args,
+	}
+}
+
+func mssql_update_many_query(tablename string, pk column, columns []column, keys []any, values [][]any) statement {
+	names := make([]string, 0, len(columns)+1)
+	names = append(names, pk.name)
+
+	sets := make([]string, len(columns))
+	for j, c := range columns {
+		names = append(names, c.name)
+		sets[j] = fmt.Sprintf("target.%s = source.%s", c.name, c.name)
+	}
+
+	var args []any
+	rows := make([]string, len(keys))
+	for i, key := range keys {
+		rows[i] = fmt.Sprintf("(%s)", placeholders(len(names)))
+		args = append(args, key)
+		args = append(args, values[i]...)
+	}
+
+	return statement{
+		query: fmt.Sprintf("update target\nset %s\nfrom %s as target\njoin (values %s) as source(%s)\non target.%s = source.%s;",
+			strings.Join(sets, ", "), tablename, strings.Join(rows, ",\n"), strings.Join(names, ", "), pk.name, pk.name),
+		args: args,
+	}
+}
+
+// nil_record returns an error naming the index of the first nil record when records is a slice of pointers
+func nil_record(records reflect.Value) error {
+	if records.Type().Elem().Kind() != reflect.Pointer {
+		return nil
+	}
+
+	for i := 0; i < records.Len(); i++ {
+		if records.Index(i).IsNil() {
+			return fmt.Errorf("record %d is <nil>", i)
+		}
+	}
+	return nil
+}
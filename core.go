@@ -6,6 +6,7 @@ import (
 	. "github.com/chapgx/assert/v2"
 	_ "github.com/go-sql-driver/mysql"
 	_ "github.com/microsoft/go-mssqldb"
+	"io/fs"
 	_ "modernc.org/sqlite"
 )
 
@@ -108,7 +109,7 @@ func new_client(engine ENGINE, conn string, dbname string) (*MORM, error) {
 		m.connect = func() error {
 			var e error
 			m.db, e = sql.Open("sqlserver", conn)
-			if e != nil {
+			if e == nil {
 				m.connected = true
 			}
 			return e
@@ -124,6 +125,24 @@ func CreateTable(model any, tablename string) error {
 	return _morm.CreateTable(model, tablename)
 }
 
+// CreateTables creates the tables of models in dependency order using the default [MORM] client.
+// See [MORM.CreateTables]
+func CreateTables(models ...any) Result {
+	return _morm.CreateTables(models...)
+}
+
+// DropTables drops the tables of models in dependency order using the default [MORM] client.
+// See [MORM.DropTables]
+func DropTables(models ...any) Result {
+	return _morm.DropTables(models...)
+}
+
+// TruncateTables deletes every record of the tables of models in dependency order using the default [MORM] client.
+// See [MORM.TruncateTables]
+func TruncateTables(models ...any) Result {
+	return _morm.TruncateTables(models...)
+}
+
 // Insert creates a new record
 func Insert(model any) error {
 	return insert(model, "", _morm)
@@ -142,6 +161,31 @@ func Update(model any, filters *Filter, fields ...string) Result {
 	return _morm.Update(model, filters, fields...)
 }
 
+// UpdateByName makes changes to specify fields in tablename. See [MORM.UpdateByName]
+func UpdateByName(model any, tablename string, filters *Filter, fields ...string) Result {
+	return _morm.UpdateByName(model, tablename, filters, fields...)
+}
+
+// UpdateExpr updates records with expressions using the default [MORM] client. See [MORM.UpdateExpr]
+func UpdateExpr(model any, filters *Filter, assignments ...Assignment) Result {
+	return _morm.UpdateExpr(model, filters, assignments...)
+}
+
+// Save inserts or updates the model by primary key using the default [MORM] client. See [MORM.Save]
+func Save(model any) Result {
+	return _morm.Save(model)
+}
+
+// Upsert inserts or updates the model using the default [MORM] client. See [MORM.Upsert]
+func Upsert(model any, conflictColumns ...string) Result {
+	return _morm.Upsert(model, conflictColumns...)
+}
+
+// UpsertWith inserts or updates the model using the default [MORM] client. See [MORM.UpsertWith]
+func UpsertWith(model any, opts UpsertOptions) Result {
+	return _morm.UpsertWith(model, opts)
+}
+
 // Exec executres arbitrary query using the underlying driver
 func Exec(query string, params ...any) (sql.Result, error) {
 	return _morm.Exec(query, params...)
@@ -165,7 +209,7 @@ func Drop(model any) error {
 }
 
 func DropByName(tablename string) error {
-	return DropByName(tablename)
+	return _morm.DropByName(tablename)
 }
 
 // DeleteByName deletes records from a tablename based on filter
@@ -178,6 +222,75 @@ func Delete(model any, filters *Filter) Result {
 	return _morm.Delete(model, filters)
 }
 
-func Read(model any, filters *Filter, tablename string) error {
-	return _morm.Read(model, filters, tablename)
+// DeleteModel deletes the record with the primary key of model using the default [MORM] client.
+// See [MORM.DeleteModel]
+func DeleteModel(model any, opts ...DeleteOption) Result {
+	return _morm.DeleteModel(model, opts...)
+}
+
+// Read reads data into the model using the default [MORM] client. See [MORM.Read]
+func Read(model any, filters *Filter, tablename string, opts ...ReadOption) error {
+	return _morm.Read(model, filters, tablename, opts...)
+}
+
+// ReadMaps reads the records of a table into maps using the default [MORM] client. See [MORM.ReadMaps]
+func ReadMaps(tablename string, filters *Filter) ([]map[string]any, error) {
+	return _morm.ReadMaps(tablename, filters)
+}
+
+// QueryMaps executes an arbitrary query and reads the rows into maps using the default [MORM] client.
+// See [MORM.QueryMaps]
+func QueryMaps(query string, params ...any) ([]map[string]any, error) {
+	return _morm.QueryMaps(query, params...)
+}
+
+// FindByID reads the record with the primary key id using the default [MORM] client. See [MORM.FindByID]
+func FindByID(model any, id any, tablename string, opts ...ReadOption) error {
+	return _morm.FindByID(model, id, tablename, opts...)
+}
+
+// First reads the first record matching filters using the default [MORM] client. See [MORM.First]
+func First(model any, filters *Filter, tablename string, opts ...ReadOption) error {
+	return _morm.First(model, filters, tablename, opts...)
+}
+
+// Exists checks if any record matches filters using the default [MORM] client
+func Exists(model any, filters *Filter, tablename string) (bool, error) {
+	return _morm.Exists(model, filters, tablename)
+}
+
+// Count returns the number of records matching filters using the default [MORM] client
+func Count(model any, filters *Filter, tablename string) (int64, error) {
+	return _morm.Count(model, filters, tablename)
+}
+
+// QueryInto executes an arbitrary query and scans the result into dest using the default [MORM] client.
+// See [MORM.QueryInto]
+func QueryInto(dest any, query string, params ...any) error {
+	return _morm.QueryInto(dest, query, params...)
+}
+
+// QueryIntoStrict is [QueryInto] failing on result columns without a field. See [MORM.QueryIntoStrict]
+func QueryIntoStrict(dest any, query string, params ...any) error {
+	return _morm.QueryIntoStrict(dest, query, params...)
+}
+
+// LoadQueries registers the queries of .sql files on the default [MORM] client. See [MORM.LoadQueries]
+func LoadQueries(fsys fs.FS, patterns ...string) error {
+	return _morm.LoadQueries(fsys, patterns...)
+}
+
+// ExecNamed executes a registered query using the default [MORM] client
+func ExecNamed(name string, params ...any) (sql.Result, error) {
+	return _morm.ExecNamed(name, params...)
+}
+
+// QueryNamed executes a registered query that returns rows using the default [MORM] client
+func QueryNamed(name string, params ...any) (*sql.Rows, error) {
+	return _morm.QueryNamed(name, params...)
+}
+
+// QueryNamedInto executes a registered query and scans the result into dest using the default [MORM] client
+func QueryNamedInto(dest any, name string, params ...any) error {
+	return _morm.QueryNamedInto(dest, name, params...)
 }
@@ -0,0 +1,136 @@
+package morm
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// DeleteOption configures how [MORM.DeleteModel] deletes a record
+type DeleteOption func(*delete_options)
+
+type delete_options struct {
+	cascade   bool
+	tablename string
+}
+
+// Cascade deletes the records of the adjecent tables linked to the record as well, nested adjecent tables included
+func Cascade() DeleteOption {
+	return func(do *delete_options) {
+		do.cascade = true
+	}
+}
+
+// InTable deletes the record from tablename instead of the table derived from the model
+func InTable(tablename string) DeleteOption {
+	return func(do *delete_options) {
+		do.tablename = tablename
+	}
+}
+
+// delete_step is the delete of the rows of a table matching a where clause
+type delete_step struct {
+	tablename string
+	where     string
+}
+
+// DeleteModel deletes the record with the primary key of model.
+//
+// With [Cascade] the records of its adjecent tables are deleted first, all in a single transaction. The Result has
+// the rows affected of every table in Tables
+func (m *MORM) DeleteModel(model any, opts ...DeleteOption) Result {
+	if m == nil {
+		return error_result(ErrDefaultClientIsNil)
+	}
+
+	var do delete_options
+	for _, opt := range opts {
+		opt(&do)
+	}
+
+	t, e := pulltype(model)
+	if e != nil {
+		return error_result(e)
+	}
+	v := pullvalue(model)
+
+	pk, ok := primary_key(t)
+	if !ok {
+		return error_result(fmt.Errorf("%w: %s records can not be deleted by key", ErrNoPrimaryKey, t.Name()))
+	}
+
+	key, e := field_arg(v.FieldByIndex(pk.index), pk.field.Type, pk.tag)
+	if e != nil {
+		return error_result(err_wrap(e, fmt.Sprintf("unable to bind %s", pk.name)))
+	}
+
+	tablename := do.tablename
+	if tablename == "" {
+		tablename = default_tablename(t)
+	}
+
+	// note: every step nests the same condition so the key is its only argument
+	where := fmt.Sprintf("where %s = ?", pk.name)
+
+	steps := []delete_step{{tablename: tablename, where: where}}
+	if do.cascade {
+		steps = cascade_steps(t, tablename, where)
+	}
+
+	tx, e := begin_tx(m)
+	if e != nil {
+		return error_result(e)
+	}
+
+	rslt := Result{Tables: make(map[string]int64, len(steps))}
+	for _, step := range steps {
+		query := statement{query: fmt.Sprintf("delete from %s\n%s;", step.tablename, step.where), args: []any{key}}
+
+		sqlr, e := query.exec(tx, m.engine)
+		if e != nil {
+			tx.Rollback()
+			return error_result(err_wrap(e, fmt.Sprintf("unable to delete from %s", step.tablename)))
+		}
+
+		n, e := sqlr.RowsAffected()
+		if e != nil {
+			tx.Rollback()
+			return error_result(e)
+		}
+
+		rslt.Tables[step.tablename] += n
+		rslt.RowsAffected += n
+	}
+
+	if e := tx.Commit(); e != nil {
+		return error_result(e)
+	}
+
+	if reflect.ValueOf(model).Kind() == reflect.Pointer {
+		m.Untrack(model)
+	}
+
+	return rslt
+}
+
+// cascade_steps returns the deletes of the rows of tablename matching where and of their adjecent tables, the
+// deepest adjecent tables first so every step can still find its parent rows.
+//
+// Adjecent tables of a struct without primary key have no link and are not reached
+func cascade_steps(t reflect.Type, tablename string, where string) []delete_step {
+	var steps []delete_step
+
+	if pk, ok := primary_key(t); ok {
+		link := link_name(t, pk)
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if tag, _ := gettag(field); !tag.IsEmpty() || field.Type.Kind() != reflect.Struct {
+				continue
+			}
+
+			childwhere := fmt.Sprintf("where %s in (select %s from %s %s)", link, pk.name, tablename, where)
+			steps = append(steps, cascade_steps(field.Type, default_tablename(field.Type), childwhere)...)
+		}
+	}
+
+	return append(steps, delete_step{tablename: tablename, where: where})
+}
@@ -42,28 +42,34 @@ func NewField(query string) Field {
 	return Field{FieldType: COLUMN, query: query}
 }
 
-// create_table_query composes the create table query
-//
-// may panic on SQLServer since it executes a create database if it does not exists
+// create_table_query composes the create table query, on SQLServer the database must exist see [create_database]
 func create_table_query(data EngineData) (string, error) {
 	switch data.m.engine {
 	case SQLITE:
 		query := sqlite_createtable_query(data.table, data.columns)
 		return query, nil
 	case SQLServer:
-		query, e := mssql_createtable_query(data.table, data.columns, data.dbname, data.m)
-		return query, e
+		return mssql_createtable_query(data.table, data.columns, data.dbname), nil
 	default:
-		panic(fmt.Sprintf("engine %s is not supported", data.m.engine))
+		return "", fmt.Errorf("%w: %s create table", ErrUnsupportedEngine, data.m.engine)
+	}
+}
+
+// create_database creates the database of the client if it names one, it runs before the tables are created and
+// outside of their transaction
+func create_database(m *MORM) error {
+	if m.engine == SQLServer {
+		return mssql_create_database(m)
 	}
+	return nil
 }
 
 // notag_column creates the sql syntax with the correct type based on the struct field type
 //
 // is possible it returns a column or a table query depending on the stuct field data type
-func notag_column(field reflect.StructField, fieldname string, m *MORM, tablename string) Field {
+func notag_column(field reflect.StructField, fieldname string, m *MORM, tablename string) (Field, error) {
 	if m == nil {
-		panic("morm client is nil")
+		return Field{}, ErrDefaultClientIsNil
 	}
 
 	switch m.engine {
@@ -72,7 +78,7 @@ func notag_column(field reflect.StructField, fieldname string, m *MORM, tablenam
 	case SQLServer:
 		return mssql_notag_column(field, fieldname, tablename)
 	default:
-		panic(fmt.Sprintf("driver %s is not supported", m.engine))
+		return Field{}, fmt.Errorf("%w: %s untagged columns", ErrUnsupportedEngine, m.engine)
 	}
 
 }
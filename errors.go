@@ -3,12 +3,47 @@ package morm
 import (
 	"errors"
 	"fmt"
+	"reflect"
 )
 
-var ErrValIsNotExpectedType = errors.New("val is not of the expected type")
+var (
+	ErrValIsNotExpectedType = errors.New("val is not of the expected type")
+	ErrNotFound             = errors.New("err record not found")
+	ErrReadTargetNotPointer = errors.New("err read target must be a non nil pointer")
+	ErrUnmappedField        = errors.New("err field does not map to a column")
+	ErrNoPrimaryKey         = errors.New("err model has no primary key")
+	ErrInvalidPageToken     = errors.New("err invalid page token")
+	ErrNotTracked           = errors.New("err record is not tracked")
+	ErrGuardViolated        = errors.New("err guard violated")
+	ErrUnsupportedType      = errors.New("err type is not supported")
+	ErrUnsupportedEngine    = errors.New("err engine is not supported")
+	ErrInvalidTag           = errors.New("err morm tag is not valid")
+)
+
+// FieldError is returned when a struct field can not be mapped to a column or bound to a query.
+// It wraps the cause so it matches errors like [ErrUnsupportedType] with [errors.Is]
+type FieldError struct {
+	// Model is the name of the struct type
+	Model string
+
+	// Field is the Go field name
+	Field string
+
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s.%s: %s", e.Model, e.Field, e.Err)
+}
+
+func (e *FieldError) Unwrap() error { return e.Err }
+
+// field_error returns a [FieldError] of the field of t
+func field_error(t reflect.Type, field reflect.StructField, e error) error {
+	return &FieldError{Model: t.Name(), Field: field.Name, Err: e}
+}
 
-// err_wrap wraps an error with a message
+// err_wrap wraps an error with a message, the error is still matched by [errors.Is]
 func err_wrap(e error, msg string) error {
-	e = fmt.Errorf("%s => %s", e.Error(), msg)
-	return e
+	return fmt.Errorf("%w => %s", e, msg)
 }
@@ -0,0 +1,147 @@
+package morm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Expr is a sql expression evaluated by the database, rendered for the client engine
+type Expr struct {
+	render func(engine ENGINE) string
+}
+
+// Now is the current timestamp of the database server
+func Now() Expr {
+	return Expr{render: func(engine ENGINE) string {
+		switch engine {
+		case SQLServer:
+			return "getdate()"
+		case MySQL:
+			return "now()"
+		default:
+			return "current_timestamp"
+		}
+	}}
+}
+
+// Raw is a sql expression passed as is to every engine
+func Raw(expression string) Expr {
+	return Expr{render: func(ENGINE) string { return expression }}
+}
+
+// Assignment is a column assignment of an expression update, see [MORM.UpdateExpr]
+type Assignment struct {
+	column string
+	render func(engine ENGINE) (statement, error)
+}
+
+// Set assigns value to column, value can be an [Expr] like [Now]
+func Set(column string, value any) Assignment {
+	return Assignment{column: column, render: func(engine ENGINE) (statement, error) {
+		if expr, ok := value.(Expr); ok {
+			return statement{query: expr.render(engine)}, nil
+		}
+
+		arg, e := filter_arg(value)
+		if e != nil {
+			return statement{}, e
+		}
+		return statement{query: "?", args: []any{arg}}, nil
+	}}
+}
+
+// Inc increments column by n, a negative n decrements it
+func Inc(column string, n any) Assignment {
+	return Assignment{column: column, render: func(ENGINE) (statement, error) {
+		arg, e := filter_arg(n)
+		if e != nil {
+			return statement{}, e
+		}
+		return statement{query: safe_keyword(column) + " + ?", args: []any{arg}}, nil
+	}}
+}
+
+// SetExpr assigns the sql expression to column, for example SetExpr("score", "score * 2")
+func SetExpr(column string, expression string) Assignment {
+	return Set(column, Raw(expression))
+}
+
+// SetColumn assigns the value of the other column in the same row to column
+func SetColumn(column string, other string) Assignment {
+	return Set(column, Raw(other))
+}
+
+// UpdateExpr updates the records of the model table matching filters with assignments evaluated by the database,
+// so counters and timestamps are updated atomically without reading the records first.
+// Every record is updated when filters is nil
+//
+//	m.UpdateExpr(Post{}, &filter, Inc("views", 1), Set("updated_at", Now()))
+func (m *MORM) UpdateExpr(model any, filters *Filter, assignments ...Assignment) Result {
+	if m == nil {
+		return error_result(ErrDefaultClientIsNil)
+	}
+
+	t, e := pulltype(model)
+	if e != nil {
+		return error_result(e)
+	}
+
+	return update_expr(default_tablename(t), filters, m, assignments...)
+}
+
+// UpdateExprByName is [MORM.UpdateExpr] where the tablename is explicit not implicit
+func (m *MORM) UpdateExprByName(tablename string, filters *Filter, assignments ...Assignment) Result {
+	if m == nil {
+		return error_result(ErrDefaultClientIsNil)
+	}
+
+	if tablename == "" {
+		return error_result(errors.New("tablename is <nil>"))
+	}
+	return update_expr(tablename, filters, m, assignments...)
+}
+
+func update_expr(tablename string, filters *Filter, m *MORM, assignments ...Assignment) Result {
+	query, e := update_expr_query(m.engine, tablename, filters, assignments...)
+	if e != nil {
+		return error_result(e)
+	}
+	return exec_write(m, query)
+}
+
+// update_expr_query composes the update of the assignments rendered for engine
+func update_expr_query(engine ENGINE, tablename string, filters *Filter, assignments ...Assignment) (statement, error) {
+	if len(assignments) == 0 {
+		return statement{}, errors.New("no assignments to update")
+	}
+
+	var args []any
+	sets := make([]string, 0, len(assignments))
+	for _, a := range assignments {
+		if a.column == "" || a.render == nil {
+			return statement{}, errors.New("assignment column is <nil>")
+		}
+
+		value, e := a.render(engine)
+		if e != nil {
+			return statement{}, err_wrap(e, fmt.Sprintf("unable to render the assignment of %s", a.column))
+		}
+
+		sets = append(sets, fmt.Sprintf("%s = %s", safe_keyword(a.column), value.query))
+		args = append(args, value.args...)
+	}
+
+	query := fmt.Sprintf("update %s\nset %s", tablename, strings.Join(sets, ", "))
+
+	if filters != nil {
+		wsql, wargs, e := filters.WhereArgs()
+		if e != nil {
+			return statement{}, e
+		}
+		query += "\n" + wsql
+		args = append(args, wargs...)
+	}
+
+	return statement{query: query + ";", args: args}, nil
+}
@@ -3,6 +3,7 @@ package morm
 import (
 	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 )
 
@@ -24,6 +25,7 @@ const (
 	LESS_THAN       FilterComparison = "<"
 	LESS_THAN_OR_EQ FilterComparison = "<="
 	IS              FilterComparison = "is"
+	IN              FilterComparison = "in"
 )
 
 type Filter struct {
@@ -66,56 +68,80 @@ func (f *Filter) Group() *FilterGroup {
 	return &fg
 }
 
+// WhereSQL renders the where clause with the values as literals, for debugging and logging only.
+// Queries are executed with [Filter.WhereArgs]
 func (f *Filter) WhereSQL() (string, error) {
-	if len(f.items) == 1 && f.groups == nil {
-		i := f.items[0]
-		val, e := anytostr(i.val)
-		if e != nil {
-			return "", e
-		}
-		query := fmt.Sprintf("where %s %s %s", i.key, i.comparison, val)
-		return query, nil
+	query, args, e := f.WhereArgs()
+	if e != nil {
+		return "", e
 	}
+	return statement{query, args}.debug(), nil
+}
 
-	clause := []string{"where"}
-	var e error
-	for idx, i := range f.items {
-		val, err := anytostr(i.val)
-		if err != nil {
-			e = err
-			break
-		}
-
-		if idx == 0 {
-			clause = append(clause, fmt.Sprintf("%s %s %s", i.key, i.comparison, val))
-		} else {
-			clause = append(clause, fmt.Sprintf("%s %s %s %s", i.separator, i.key, i.comparison, val))
-		}
+// WhereArgs returns the where clause with ? placeholders and the values bound to them in order
+func (f *Filter) WhereArgs() (string, []any, error) {
+	if len(f.items) == 0 && len(f.groups) == 0 {
+		return "", nil, errors.New("filter has no conditions")
 	}
 
+	conditions, args, e := filter_conditions(f.items)
 	if e != nil {
-		return "", e
+		return "", nil, e
 	}
 
-	query := strings.Join(clause, " ")
-	if f.groups == nil {
-		return query, nil
+	// note: groups are joined with and, the items are enclosed so their or separators don't bind to the groups
+	if len(f.items) > 0 && len(f.groups) > 0 {
+		conditions = "(" + conditions + ")"
 	}
 
-	for _, g := range f.groups {
-		sqlg, err := g.SQL()
-		if err != nil {
-			e = err
-			break
+	query := "where " + conditions
+	for idx, g := range f.groups {
+		sqlg, groupargs, e := g.args()
+		if e != nil {
+			return "", nil, e
+		}
+
+		// note: without items the first group opens the where clause
+		if idx == 0 && len(f.items) == 0 {
+			query += strings.TrimPrefix(sqlg, "and ")
+		} else {
+			query += "\n" + sqlg
 		}
-		query += "\n" + sqlg
+		args = append(args, groupargs...)
 	}
 
-	if e != nil {
-		return "", e
+	return query, args, nil
+}
+
+// filter_conditions renders the items joined by their separators with ? placeholders for their values
+func filter_conditions(items []FilterItem) (string, []any, error) {
+	var clause []string
+	var args []any
+	for idx, i := range items {
+		placeholder, itemargs, e := i.placeholder()
+		if e != nil {
+			return "", nil, e
+		}
+		args = append(args, itemargs...)
+
+		if idx == 0 {
+			clause = append(clause, fmt.Sprintf("%s %s %s", i.key, i.comparison, placeholder))
+			continue
+		}
+
+		clause = append(clause, fmt.Sprintf("%s %s %s %s", i.separator, i.key, i.comparison, placeholder))
 	}
 
-	return query, nil
+	return strings.Join(clause, " "), args, nil
+}
+
+// clone returns a copy of the filter that can be extended without changing the original
+func (f *Filter) clone() Filter {
+	c := Filter{items: append([]FilterItem{}, f.items...)}
+	if f.groups != nil {
+		c.groups = append([]*FilterGroup{}, f.groups...)
+	}
+	return c
 }
 
 type FilterGroup struct {
@@ -148,39 +174,31 @@ func (fg *FilterGroup) isnull(key string, sep FilterSeparator) *FilterGroup {
 	return fg
 }
 
+// SQL renders the group with the values as literals, for debugging and logging only
 func (fg *FilterGroup) SQL() (string, error) {
-	if fg.items == nil {
-		return "", errors.New("group items is <nil>")
+	query, args, e := fg.args()
+	if e != nil {
+		return "", e
 	}
+	return statement{query, args}.debug(), nil
+}
 
-	if len(fg.items) == 0 {
-		return "", errors.New("group items length is 0")
+// args returns the group with ? placeholders and the values bound to them in order
+func (fg *FilterGroup) args() (string, []any, error) {
+	if fg.items == nil {
+		return "", nil, errors.New("group items is <nil>")
 	}
 
-	var clause []string
-	var e error
-	for idx, i := range fg.items {
-		val, err := anytostr(i.val)
-		if err != nil {
-			e = err
-			break
-		}
-
-		if idx == 0 {
-			clause = append(clause, fmt.Sprintf("%s %s %s", i.key, i.comparison, val))
-			continue
-		}
-
-		clause = append(clause, fmt.Sprintf("%s %s %s %s", i.separator, i.key, i.comparison, val))
+	if len(fg.items) == 0 {
+		return "", nil, errors.New("group items length is 0")
 	}
 
+	conditions, args, e := filter_conditions(fg.items)
 	if e != nil {
-		return "", e
+		return "", nil, e
 	}
 
-	query := fmt.Sprintf("and (%s)", strings.Join(clause, " "))
-
-	return query, nil
+	return fmt.Sprintf("and (%s)", conditions), args, nil
 }
 
 type FilterItem struct {
@@ -190,6 +208,43 @@ type FilterItem struct {
 	separator  FilterSeparator
 }
 
+// placeholder returns the placeholders of the item value and the args bound to them.
+//
+// nil is rendered as null and containers, for the IN comparison, as a list of placeholders
+func (i FilterItem) placeholder() (string, []any, error) {
+	if i.val == nil {
+		return "null", nil, nil
+	}
+
+	v := reflect.ValueOf(i.val)
+	if (v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8) || v.Kind() == reflect.Array {
+		if v.Len() == 0 {
+			return "(null)", nil, nil
+		}
+
+		args := make([]any, v.Len())
+		for idx := range args {
+			arg, e := filter_arg(v.Index(idx).Interface())
+			if e != nil {
+				return "", nil, err_wrap(e, fmt.Sprintf("unable to bind %s", i.key))
+			}
+			args[idx] = arg
+		}
+		return fmt.Sprintf("(%s)", placeholders(len(args))), args, nil
+	}
+
+	arg, e := filter_arg(i.val)
+	if e != nil {
+		return "", nil, err_wrap(e, fmt.Sprintf("unable to bind %s", i.key))
+	}
+
+	if arg == nil {
+		return "null", nil, nil
+	}
+
+	return "?", []any{arg}, nil
+}
+
 func NewFilter() Filter {
 	return Filter{items: make([]FilterItem, 0)}
 }
@@ -0,0 +1,187 @@
+package morm
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// FindByID reads the record with the primary key id into model, a pointer to a struct.
+//
+// The primary key is the field tagged as primary, for example `morm:"id integer primary"`. [ErrNoPrimaryKey]
+// is returned if the model has none and [ErrNotFound] if no record has the id
+func (m *MORM) FindByID(model any, id any, tablename string, opts ...ReadOption) error {
+	if m == nil {
+		return ErrDefaultClientIsNil
+	}
+
+	ro, e := new_read_options(opts)
+	if e != nil {
+		return e
+	}
+
+	t, e := record_type(model)
+	if e != nil {
+		return e
+	}
+	if ro.source != nil {
+		t = ro.source
+	}
+
+	pk, ok := primary_key(t)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNoPrimaryKey, t.Name())
+	}
+
+	filters := NewFilter()
+	filters.And(pk.name, EQUAL, id)
+
+	return read(model, &filters, m, tablename, ro)
+}
+
+// First reads the first record matching filters into model, a pointer to a struct.
+//
+// Records are ordered by primary key when the model has one. [ErrNotFound] is returned if none matches
+func (m *MORM) First(model any, filters *Filter, tablename string, opts ...ReadOption) error {
+	if m == nil {
+		return ErrDefaultClientIsNil
+	}
+
+	ro, e := new_read_options(opts)
+	if e != nil {
+		return e
+	}
+
+	t, e := record_type(model)
+	if e != nil {
+		return e
+	}
+	if ro.source != nil {
+		t = ro.source
+	}
+
+	if len(ro.orderby) == 0 {
+		if pk, ok := primary_key(t); ok {
+			ro.orderby = []Order{Asc(pk.name)}
+		}
+	}
+
+	return read(model, filters, m, tablename, ro)
+}
+
+// Exists checks if any record of the model table matches filters
+func (m *MORM) Exists(model any, filters *Filter, tablename string) (bool, error) {
+	if m == nil {
+		return false, ErrDefaultClientIsNil
+	}
+
+	var found int64
+	e := scalar_query("1", true, model, filters, m, tablename, &found)
+	if e == ErrNotFound {
+		return false, nil
+	}
+
+	return e == nil, e
+}
+
+// Count returns the number of records of the model table matching filters
+func (m *MORM) Count(model any, filters *Filter, tablename string) (int64, error) {
+	if m == nil {
+		return 0, ErrDefaultClientIsNil
+	}
+
+	var count int64
+	e := scalar_query("count(*)", false, model, filters, m, tablename, &count)
+	return count, e
+}
+
+// scalar_query selects expression from the model table and scans the single value returned into dest.
+//
+// limited restricts the selection to one row. [ErrNotFound] is returned if there are no rows
+func scalar_query(expression string, limited bool, model any, filters *Filter, m *MORM, tablename string, dest any) error {
+	if tablename == "" {
+		t, e := record_type(model)
+		if e != nil {
+			return e
+		}
+		tablename = default_tablename(t)
+	}
+
+	var query string
+	switch m.engine {
+	case SQLITE:
+		query = fmt.Sprintf("select %s\nfrom %s", expression, tablename)
+	case SQLServer:
+		if limited {
+			expression = "top(1) " + expression
+		}
+		query = fmt.Sprintf("select %s\nfrom %s", expression, tablename)
+	default:
+		return fmt.Errorf("%w: %s reads", ErrUnsupportedEngine, m.engine)
+	}
+
+	var args []any
+	if filters != nil {
+		wsql, wargs, e := filters.WhereArgs()
+		if e != nil {
+			return e
+		}
+		query += "\n" + wsql
+		args = wargs
+	}
+
+	if m.engine == SQLITE && limited {
+		query += "\nLIMIT 1"
+	}
+	query += ";"
+
+	if !m.connected {
+		e := m.connect()
+		if e != nil {
+			return e
+		}
+	}
+
+	switch m.engine {
+	case SQLServer:
+		usedb, e := mssql_use_db(m)
+		if e != nil {
+			return e
+		}
+		query = usedb + query
+	}
+
+	rows, e := statement{query: query, args: args}.query_rows(m.db, m.engine)
+	if e != nil {
+		return e
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if e := rows.Err(); e != nil {
+			return e
+		}
+		return ErrNotFound
+	}
+
+	e = rows.Scan(dest)
+	if e != nil {
+		return e
+	}
+
+	return rows.Err()
+}
+
+// record_type returns the struct type of a model, a struct, a pointer to it or a slice of either
+func record_type(model any) (reflect.Type, error) {
+	t := reflect.TypeOf(model)
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = t.Elem()
+	}
+	if t == nil {
+		return nil, fmt.Errorf("%w: model is <nil>", ErrUnsupportedType)
+	}
+	return pulltype(t)
+}
@@ -0,0 +1,212 @@
+package morm
+
+import (
+	"errors"
+	"fmt"
+)
+
+// GuardOption is a limit a guarded mutation must respect, see [MORM.Guarded]
+type GuardOption func(*guard_options)
+
+type guard_options struct {
+	expect    int64
+	expecting bool
+
+	max    int64
+	capped bool
+
+	updateall bool
+	deleteall bool
+}
+
+// ExpectRows rolls back the mutation unless exactly n rows are affected
+func ExpectRows(n int64) GuardOption {
+	return func(g *guard_options) {
+		g.expect = n
+		g.expecting = true
+	}
+}
+
+// MaxRows rolls back the mutation if more than n rows are affected
+func MaxRows(n int64) GuardOption {
+	return func(g *guard_options) {
+		g.max = n
+		g.capped = true
+	}
+}
+
+// UpdateAll allows guarded updates without filters, which update every record
+func UpdateAll() GuardOption {
+	return func(g *guard_options) {
+		g.updateall = true
+	}
+}
+
+// DeleteAll allows guarded deletes without filters, which delete every record
+func DeleteAll() GuardOption {
+	return func(g *guard_options) {
+		g.deleteall = true
+	}
+}
+
+// GuardError is returned when a guarded mutation is rolled back or refused because it violates a guard.
+// It matches [ErrGuardViolated] with [errors.Is]
+type GuardError struct {
+	// Guard is the violated guard, ExpectRows, MaxRows, UpdateAll or DeleteAll
+	Guard string
+
+	// Limit is the row count of the guard
+	Limit int64
+
+	// Affected are the rows the mutation affected before being rolled back
+	Affected int64
+}
+
+func (e *GuardError) Error() string {
+	switch e.Guard {
+	case "UpdateAll", "DeleteAll":
+		return fmt.Sprintf("%s: filters are <nil> and %s was not set", ErrGuardViolated, e.Guard)
+	default:
+		return fmt.Sprintf("%s: %s(%d) but %d rows were affected", ErrGuardViolated, e.Guard, e.Limit, e.Affected)
+	}
+}
+
+func (e *GuardError) Is(target error) bool { return target == ErrGuardViolated }
+
+// Guarded is a view of a [MORM] client that runs updates and deletes in a transaction and rolls them back when the
+// rows affected violate its guards. Mutations without filters are refused unless explicitly allowed
+type Guarded struct {
+	m      *MORM
+	guards guard_options
+}
+
+// Guarded returns a view of the client which mutations are checked against guards
+//
+//	rslt := m.Guarded(morm.ExpectRows(1)).Update(&user, &filter, "Email")
+//	if errors.Is(rslt.Error, morm.ErrGuardViolated) {
+func (m *MORM) Guarded(guards ...GuardOption) *Guarded {
+	g := Guarded{m: m}
+	for _, guard := range guards {
+		guard(&g.guards)
+	}
+	return &g
+}
+
+// Update makes changes to the fields of the records matching filters, see [MORM.Update].
+// The guards apply to the rows affected of the model table, or of the first adjecent table when only adjecent
+// fields are updated. Result.Tables has the rows affected of every table
+func (g *Guarded) Update(model any, filters *Filter, fields ...string) Result {
+	return g.UpdateByName(model, "", filters, fields...)
+}
+
+// UpdateByName is [Guarded.Update] where the tablename is explicit, empty derives it from the model
+func (g *Guarded) UpdateByName(model any, tablename string, filters *Filter, fields ...string) Result {
+	if g.m == nil {
+		return error_result(ErrDefaultClientIsNil)
+	}
+
+	if filters == nil && !g.guards.updateall {
+		return error_result(&GuardError{Guard: "UpdateAll"})
+	}
+
+	queries, e := update_queries(model, tablename, filters, fields...)
+	if e != nil {
+		return error_result(e)
+	}
+	return g.exec(queries)
+}
+
+// UpdateExpr updates the records matching filters with expressions, see [MORM.UpdateExpr]
+func (g *Guarded) UpdateExpr(model any, filters *Filter, assignments ...Assignment) Result {
+	if g.m == nil {
+		return error_result(ErrDefaultClientIsNil)
+	}
+
+	if filters == nil && !g.guards.updateall {
+		return error_result(&GuardError{Guard: "UpdateAll"})
+	}
+
+	t, e := pulltype(model)
+	if e != nil {
+		return error_result(e)
+	}
+
+	tablename := default_tablename(t)
+	query, e := update_expr_query(g.m.engine, tablename, filters, assignments...)
+	if e != nil {
+		return error_result(e)
+	}
+	return g.exec([]table_statement{{query, tablename}})
+}
+
+// Delete deletes the records of the model table matching filters
+func (g *Guarded) Delete(model any, filters *Filter) Result {
+	t, e := pulltype(model)
+	if e != nil {
+		return error_result(e)
+	}
+	return g.DeleteByName(default_tablename(t), filters)
+}
+
+// DeleteByName deletes the records of tablename matching filters
+func (g *Guarded) DeleteByName(tablename string, filters *Filter) Result {
+	if g.m == nil {
+		return error_result(ErrDefaultClientIsNil)
+	}
+
+	if tablename == "" {
+		return error_result(errors.New("tablename is <nil>"))
+	}
+
+	if filters == nil {
+		if !g.guards.deleteall {
+			return error_result(&GuardError{Guard: "DeleteAll"})
+		}
+		return g.exec([]table_statement{{statement{query: fmt.Sprintf("delete from %s;", tablename)}, tablename}})
+	}
+
+	query, e := delete_query(tablename, filters)
+	if e != nil {
+		return error_result(e)
+	}
+	return g.exec([]table_statement{{query, tablename}})
+}
+
+// exec executes the queries in a transaction and commits only if the rows affected of the first table respect the
+// guards, the updates of adjecent tables follow the update of their parent
+func (g *Guarded) exec(queries []table_statement) Result {
+	tx, e := begin_tx(g.m)
+	if e != nil {
+		return error_result(e)
+	}
+
+	rslt := exec_tx(tx, queries, g.m.engine)
+	if rslt.Error != nil {
+		tx.Rollback()
+		return rslt
+	}
+
+	if e := g.guards.check(rslt.Tables[queries[0].tablename]); e != nil {
+		tx.Rollback()
+		return error_result(e)
+	}
+
+	if e := tx.Commit(); e != nil {
+		return error_result(e)
+	}
+
+	return rslt
+}
+
+// check returns a [GuardError] if affected violates a guard
+func (g guard_options) check(affected int64) error {
+	if g.expecting && affected != g.expect {
+		return &GuardError{Guard: "ExpectRows", Limit: g.expect, Affected: affected}
+	}
+
+	if g.capped && affected > g.max {
+		return &GuardError{Guard: "MaxRows", Limit: g.max, Affected: affected}
+	}
+
+	return nil
+}
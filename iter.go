@@ -0,0 +1,78 @@
+package morm
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"reflect"
+)
+
+// ReadIter streams the records matching filters one row at a time instead of reading them all into memory.
+//
+// T must be a struct, the table name is derived from it when tablename is empty. The underlying rows are closed
+// when the iteration ends or the consumer breaks out of the loop. An error ends the iteration.
+// Relations can not be loaded while streaming.
+//
+//	for u, e := range morm.ReadIter[User](m, &filter, "") {
+//		if e != nil {
+//			return e
+//		}
+//		...
+//	}
+func ReadIter[T any](m *MORM, filters *Filter, tablename string, opts ...ReadOption) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		t := reflect.TypeFor[T]()
+		if t.Kind() != reflect.Struct {
+			yield(zero, fmt.Errorf("%w: unable to read into %s, expected a struct", ErrUnsupportedType, t))
+			return
+		}
+
+		if m == nil {
+			yield(zero, ErrDefaultClientIsNil)
+			return
+		}
+
+		ro, e := new_read_options(opts)
+		if e != nil {
+			yield(zero, e)
+			return
+		}
+
+		if len(ro.relations) > 0 {
+			yield(zero, errors.New("relations can not be loaded while streaming"))
+			return
+		}
+
+		// note: streamed records are copies so there is no address to track
+		if ro.tracked {
+			yield(zero, errors.New("records can not be tracked while streaming"))
+			return
+		}
+
+		rows, columns, e := select_rows(t, filters, m, tablename, true, ro)
+		if e != nil {
+			yield(zero, e)
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var record T
+			e := scan_row(rows, reflect.ValueOf(&record).Elem(), columns)
+			if e != nil {
+				yield(zero, e)
+				return
+			}
+
+			if !yield(record, nil) {
+				return
+			}
+		}
+
+		if e := rows.Err(); e != nil {
+			yield(zero, e)
+		}
+	}
+}
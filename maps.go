@@ -0,0 +1,149 @@
+package morm
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// ReadMaps reads the records of a table into maps of column name to value, for tables without a Go struct.
+//
+// filters is optional, values are normalized per engine (see [MORM.QueryMaps])
+func (m *MORM) ReadMaps(tablename string, filters *Filter) ([]map[string]any, error) {
+	if m == nil {
+		return nil, ErrDefaultClientIsNil
+	}
+
+	if tablename == "" {
+		return nil, fmt.Errorf("tablename is <nil>")
+	}
+
+	query := statement{query: fmt.Sprintf("select *\nfrom %s", tablename)}
+	if filters != nil {
+		wsql, args, e := filters.WhereArgs()
+		if e != nil {
+			return nil, e
+		}
+		query.query += "\n" + wsql
+		query.args = args
+	}
+	query.query += ";"
+
+	if !m.connected {
+		e := m.connect()
+		if e != nil {
+			return nil, e
+		}
+	}
+
+	switch m.engine {
+	case SQLServer:
+		usedb, e := mssql_use_db(m)
+		if e != nil {
+			return nil, e
+		}
+		query.query = usedb + query.query
+	}
+
+	rows, e := query.query_rows(m.db, m.engine)
+	if e != nil {
+		return nil, e
+	}
+	defer rows.Close()
+
+	return scan_maps(rows, m.engine)
+}
+
+// QueryMaps executes an arbitrary query and reads every row into a map of column name to value.
+//
+// Values are normalized so they are usable without knowing the engine. Text returned as bytes becomes a string,
+// SQL Server bit is a bool, datetime2 a [time.Time], uniqueidentifier its string form and decimals their
+// string representation. SQLite integer, real, text and blob are int64, float64, string and []byte.
+func (m *MORM) QueryMaps(query string, params ...any) ([]map[string]any, error) {
+	if m == nil {
+		return nil, ErrDefaultClientIsNil
+	}
+
+	rows, e := m.Query(query, params...)
+	if e != nil {
+		return nil, e
+	}
+	defer rows.Close()
+
+	_queryHistory = append(_queryHistory, query)
+
+	return scan_maps(rows, m.engine)
+}
+
+// scan_maps reads every row into a map of column name to the normalized value
+func scan_maps(rows *sql.Rows, engine ENGINE) ([]map[string]any, error) {
+	columntypes, e := rows.ColumnTypes()
+	if e != nil {
+		return nil, e
+	}
+
+	records := make([]map[string]any, 0)
+	for rows.Next() {
+		values := make([]any, len(columntypes))
+		dest := make([]any, len(columntypes))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+
+		e := rows.Scan(dest...)
+		if e != nil {
+			return nil, e
+		}
+
+		record := make(map[string]any, len(columntypes))
+		for i, ct := range columntypes {
+			record[ct.Name()] = normalize_value(engine, strings.ToUpper(ct.DatabaseTypeName()), values[i])
+		}
+		records = append(records, record)
+	}
+
+	if e := rows.Err(); e != nil {
+		return nil, e
+	}
+
+	return records, nil
+}
+
+// normalize_value turns a value returned by the driver of engine into its natural Go representation based on
+// the database type name of the column
+func normalize_value(engine ENGINE, dbtype string, val any) any {
+	b, ok := val.([]byte)
+	if !ok {
+		return val
+	}
+
+	switch engine {
+	case SQLServer:
+		switch dbtype {
+		case "UNIQUEIDENTIFIER":
+			if len(b) == 16 {
+				return mssql_uuid(b)
+			}
+		case "BINARY", "VARBINARY", "IMAGE", "TIMESTAMP", "ROWVERSION":
+			return append([]byte(nil), b...)
+		}
+		return string(b)
+	default:
+		switch dbtype {
+		case "", "BLOB", "BINARY", "VARBINARY", "TINYBLOB", "MEDIUMBLOB", "LONGBLOB":
+			return append([]byte(nil), b...)
+		}
+		return string(b)
+	}
+}
+
+// mssql_uuid formats the bytes of a uniqueidentifier, the first three groups are stored little endian
+func mssql_uuid(b []byte) string {
+	return fmt.Sprintf("%X-%X-%X-%X-%X",
+		[]byte{b[3], b[2], b[1], b[0]},
+		[]byte{b[5], b[4]},
+		[]byte{b[7], b[6]},
+		b[8:10],
+		b[10:],
+	)
+}
@@ -4,9 +4,9 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	. "github.com/chapgx/assert/v2"
 	"reflect"
 	"strings"
+	"sync"
 )
 
 // NOTE: for dev only
@@ -33,10 +33,26 @@ type MORM struct {
 	connect      FnConnect
 	databasename string
 	info         DBInfo
+
+	// secret page tokens are signed with
+	pagesecret   []byte
+	pagesecretmu sync.Mutex
+
+	// registered queries by name and engine
+	queries map[string]map[ENGINE]string
+
+	// snapshots of tracked records by address
+	snapshots   map[any]snapshot
+	snapshotsmu sync.Mutex
 }
 
 // GetDatabaseName returns the databasename is any
-func (m *MORM) GetDatabaseName() string { return m.databasename }
+func (m *MORM) GetDatabaseName() string {
+	if m == nil {
+		return ""
+	}
+	return m.databasename
+}
 
 // Close() closes the database connection and resets [MORM]
 func (m *MORM) Close() error {
@@ -57,32 +73,75 @@ func (m *MORM) Close() error {
 
 // CreateTable creates a table base on the model and optional tablename
 func (m *MORM) CreateTable(model any, tablename string) error {
-	createdepth++
+	if m == nil {
+		return ErrDefaultClientIsNil
+	}
 
-	t := pulltype(model)
+	return m.create_table(model, tablename, nil)
+}
 
-	if tablename == "" {
-		tablename = strings.ToLower(t.Name())
-		if !strings.HasSuffix(tablename, "s") {
-			tablename += "s"
+// create_table creates the table of the model and the adjecent tables of its untagged struct fields.
+//
+// link is the column referencing the parent record when the table is adjecent, it can be nil
+func (m *MORM) create_table(model any, tablename string, link *adjecent_link) error {
+	tables, e := schema_tables(model, tablename, "", link)
+	if e != nil {
+		return e
+	}
+
+	e = create_database(m)
+	if e != nil {
+		return e
+	}
+
+	for _, table := range tables {
+		query, e := m.table_query(table.model, table.tablename, table.link)
+		if e != nil {
+			return e
+		}
+
+		if !m.connected {
+			e := m.connect()
+			if e != nil {
+				return err_wrap(e, "unable to connect")
+			}
+		}
+
+		_queryHistory = append(_queryHistory, query)
+		_, e = m.db.Exec(query)
+		if e != nil {
+			return err_wrap(e, fmt.Sprintf("unable to create table %s", table.tablename))
 		}
 	}
 
+	return nil
+}
+
+// table_query composes the create table query of the model table, adjecent tables are not included.
+//
+// link is the column referencing the parent record when the table is adjecent, it can be nil
+func (m *MORM) table_query(t reflect.Type, tablename string, link *adjecent_link) (string, error) {
+	createdepth++
+	defer func() {
+		if createdepth == 1 {
+			seen = make(map[string]struct{})
+		}
+		createdepth--
+	}()
+
 	var columns []string
 	var follow_up_queries []string
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
-		mormtag := gettag(field)
+		mormtag, e := gettag(field)
+		if e != nil {
+			return "", field_error(t, field, e)
+		}
 
 		// note: untagged are added as text with their field name
 		if mormtag.IsEmpty() {
-			//BUG: adjecent structs are not being created with a  link or foreign key
-
+			// note: adjecent structs have their own table
 			if field.Type.Kind() == reflect.Struct {
-				e := m.CreateTable(field.Type, "")
-				if e != nil {
-					panic(e)
-				}
 				continue
 			}
 
@@ -90,7 +149,10 @@ func (m *MORM) CreateTable(model any, tablename string) error {
 			seen[fieldname] = struct{}{}
 			fieldname = safe_keyword(fieldname)
 
-			column := notag_column(field, fieldname, m, tablename)
+			column, e := notag_column(field, fieldname, m, tablename)
+			if e != nil {
+				return "", field_error(t, field, e)
+			}
 
 			if column.FieldType == COLUMN {
 				columns = append(columns, column.query)
@@ -107,13 +169,9 @@ func (m *MORM) CreateTable(model any, tablename string) error {
 			case IgnoreDirective:
 				continue
 			case FlattenDirective:
-				if field.Type.Kind() != reflect.Struct {
-					break
-				}
-
 				cols, e := extract_columns(field.Type, m)
 				if e != nil {
-					return e
+					return "", e
 				}
 				columns = append(columns, cols...)
 				continue
@@ -136,131 +194,92 @@ func (m *MORM) CreateTable(model any, tablename string) error {
 
 	}
 
-	query, e := create_table_query(EngineData{table: tablename, columns: strings.Join(columns, ","), dbname: m.databasename, m: m})
-	if e != nil {
-		return e
-	}
-
-	if !m.connected {
-		e := m.connect()
-		if e != nil {
-			panic(e)
-		}
-	}
-
-	_queryHistory = append(_queryHistory, query)
-	_, e = m.db.Exec(query)
-	if e != nil {
-		panic(e)
+	if link != nil {
+		columns = append(columns, fmt.Sprintf("%s %s", link.name, link.sqltype))
 	}
 
 	//TODO: 2026-02-05 handle follow_up_queries (do this before )
 
-	if createdepth == 1 {
-		seen = make(map[string]struct{})
-	}
-
-	createdepth--
-	return nil
+	return create_table_query(EngineData{table: tablename, columns: strings.Join(columns, ","), dbname: m.databasename, m: m})
 }
 
 // Insert creates a new record
 func (m *MORM) Insert(model any) error {
+	if m == nil {
+		return ErrDefaultClientIsNil
+	}
+
 	return insert(model, "", m)
 }
 
 // InsertByName creates a new record where the tablename is explicit not implicit
 func (m *MORM) InsertByName(model any, tablename string) error {
+	if m == nil {
+		return ErrDefaultClientIsNil
+	}
+
 	if tablename == "" {
 		return errors.New("tablename is <nil>")
 	}
 	return insert(model, tablename, m)
 }
 
-// Update makes changes to specify fields in the database
+// Update makes changes to specify fields in the database.
+//
+// fields are Go field names or dotted paths, Phone.Number updates the number of a flattened Phone struct and
+// Address.City the city of the adjecent Address table of the records matching filters
 func (m *MORM) Update(model any, filters *Filter, fields ...string) Result {
-	t := pulltype(model)
-	v := pullvalue(model)
-	var e error
-
-	query := fmt.Sprintf("update %ss\nset", strings.ToLower(t.Name()))
-
-	var fieldsandvalues []string
-	for _, field := range fields {
-		fvalue := v.FieldByName(field)
-
-		f, ok := t.FieldByName(field)
-		if !ok {
-			e = fmt.Errorf("%s field not found", field)
-			break
-		}
-
-		mtag := gettag(f)
-
-		// TODO: needs a nil check for map, chan pointers and slices
+	if m == nil {
+		return error_result(ErrDefaultClientIsNil)
+	}
 
-		val, err := tostring(fvalue, f.Type, mtag)
-		if err != nil {
-			e = err
-			break
-		}
+	return update(model, "", filters, m, fields...)
+}
 
-		fieldsandvalues = append(fieldsandvalues, fmt.Sprintf("%s=%s", mtag.fieldname, val))
+// UpdateByName makes changes to the fields of the records in tablename matching filters, the table an
+// [InsertByName] wrote to. See [MORM.Update]
+func (m *MORM) UpdateByName(model any, tablename string, filters *Filter, fields ...string) Result {
+	if m == nil {
+		return error_result(ErrDefaultClientIsNil)
 	}
 
-	if e != nil {
-		return new_result(e, 0)
+	if tablename == "" {
+		return error_result(errors.New("tablename is <nil>"))
 	}
+	return update(model, tablename, filters, m, fields...)
+}
 
-	query = fmt.Sprintf("%s %s", query, strings.Join(fieldsandvalues, ","))
-
-	if filters != nil {
-		wsql, e := filters.WhereSQL()
-		if e != nil {
-			return error_result(e)
-		}
-		query += "\n" + wsql
+// Exec executres arbitrary query using the underlying driver.
+//
+// When params is a single map[string]any or struct, :name placeholders in the query are bound by name and rewritten
+// to the engine placeholders. Struct values are looked up by morm column name or Go field name
+//
+//	m.Exec("update users set alias = :alias where id = :id", map[string]any{"id": "00", "alias": "boss"})
+func (m *MORM) Exec(query string, params ...any) (sql.Result, error) {
+	if m == nil {
+		return nil, ErrDefaultClientIsNil
 	}
 
-	query += ";"
-
-	_queryHistory = append(_queryHistory, query)
-
 	if !m.connected {
 		e := m.connect()
-		// NOTE: maybe i don't crash here and try to recover
-		Assert(e == nil, e)
-	}
-
-	switch m.engine {
-	case SQLServer:
-		usedb, e := mssql_use_db(m)
-		Assert(e == nil, e)
-		query = usedb + query
+		if e != nil {
+			return nil, e
+		}
 	}
 
-	rslt, e := m.db.Exec(query)
-
+	query, params, e := bind_named(m.engine, query, params)
 	if e != nil {
-		return new_result(e, 0)
+		return nil, e
 	}
 
-	affected, e := rslt.RowsAffected()
-
-	return new_result(e, affected)
-}
-
-// Exec executres arbitrary query using the underlying driver
-func (m *MORM) Exec(query string, params ...any) (sql.Result, error) {
-	if !m.connected {
-		e := m.connect()
-		Assert(e == nil, e)
-	}
 	return m.db.Exec(query, params...)
 }
 
+// Query executes a query that returns rows, params can be named see [MORM.Exec]
 func (m *MORM) Query(query string, params ...any) (*sql.Rows, error) {
-	Assert(m != nil, "morm instance not initiated")
+	if m == nil {
+		return nil, ErrDefaultClientIsNil
+	}
 
 	if !m.connected {
 		e := m.connect()
@@ -269,11 +288,19 @@ func (m *MORM) Query(query string, params ...any) (*sql.Rows, error) {
 		}
 	}
 
+	query, params, e := bind_named(m.engine, query, params)
+	if e != nil {
+		return nil, e
+	}
+
 	return m.db.Query(query, params...)
 }
 
+// QueryRow executes a query that returns at most one row, params can be named see [MORM.Exec]
 func (m *MORM) QueryRow(query string, params ...any) (*sql.Row, error) {
-	Assert(m != nil, "morm instance not initiated")
+	if m == nil {
+		return nil, ErrDefaultClientIsNil
+	}
 
 	if !m.connected {
 		e := m.connect()
@@ -282,32 +309,59 @@ func (m *MORM) QueryRow(query string, params ...any) (*sql.Row, error) {
 		}
 	}
 
+	query, params, e := bind_named(m.engine, query, params)
+	if e != nil {
+		return nil, e
+	}
+
 	return m.db.QueryRow(query, params...), nil
 }
 
+// Drop drops the table of the model and its adjecent tables, see [MORM.DropTables]
 func (m *MORM) Drop(model any) error {
-	t := pulltype(model)
-	return drop(t.Name()+"s", m)
+	if m == nil {
+		return ErrDefaultClientIsNil
+	}
+
+	return m.DropTables(model).Error
 }
 
 func (m *MORM) DropByName(tablename string) error {
+	if m == nil {
+		return ErrDefaultClientIsNil
+	}
+
 	return drop(tablename, m)
 }
 
 // DeleteByName deletes records from a tablename based on filter
 func (m *MORM) DeleteByName(tablename string, filters *Filter) Result {
-	return delete(tablename, filters, m)
+	if m == nil {
+		return error_result(ErrDefaultClientIsNil)
+	}
+
+	return delete_records(tablename, filters, m)
 }
 
 // Delete deletes a record from the table representation of the model passed in
 func (m *MORM) Delete(model any, filters *Filter) Result {
-	t := pulltype(model)
-	tablename := strings.ToLower(t.Name()) + "s"
-	return delete(tablename, filters, m)
+	if m == nil {
+		return error_result(ErrDefaultClientIsNil)
+	}
+
+	t, e := pulltype(model)
+	if e != nil {
+		return error_result(e)
+	}
+
+	return delete_records(default_tablename(t), filters, m)
 }
 
 // Info returns server information
 func (m *MORM) Info() (DBInfo, error) {
+	if m == nil {
+		return nil, ErrDefaultClientIsNil
+	}
 
 	switch m.engine {
 	case SQLServer:
@@ -317,12 +371,25 @@ func (m *MORM) Info() (DBInfo, error) {
 		}
 		return info, nil
 	default:
-		return nil, fmt.Errorf("engine %s is not supported for server info", m.engine)
+		return nil, fmt.Errorf("%w: %s server info", ErrUnsupportedEngine, m.engine)
 	}
 
 }
 
-// Read read data into the model
-func (m *MORM) Read(model any, filters *Filter, tablename string) error {
-	return read(model, filters, m, tablename)
+// Read reads data into the model.
+//
+// model is a pointer to a struct to read a single record, [ErrNotFound] is returned when there is none,
+// or a pointer to a slice of structs to read every record matching the filters.
+// If tablename is empty the table name is derived from the struct name like [MORM.CreateTable] does
+func (m *MORM) Read(model any, filters *Filter, tablename string, opts ...ReadOption) error {
+	if m == nil {
+		return ErrDefaultClientIsNil
+	}
+
+	ro, e := new_read_options(opts)
+	if e != nil {
+		return e
+	}
+
+	return read(model, filters, m, tablename, ro)
 }
@@ -0,0 +1,126 @@
+package morm
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// named_params returns the values to bind by name when params is a single map[string]any or struct.
+//
+// Struct values are keyed by their column name (morm tag or lowercase field name) and by Go field name
+func named_params(params []any) (map[string]any, bool, error) {
+	if len(params) != 1 {
+		return nil, false, nil
+	}
+
+	switch p := params[0].(type) {
+	case map[string]any:
+		return p, true, nil
+	case sql.NamedArg, driver.Valuer, time.Time:
+		return nil, false, nil
+	}
+
+	v := reflect.ValueOf(params[0])
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, false, nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil, false, nil
+	}
+
+	columns, e := model_columns(v.Type())
+	if e != nil {
+		return nil, false, e
+	}
+
+	values := make(map[string]any)
+	for _, c := range columns {
+		value := stored_value(v.FieldByIndex(c.index), c)
+		values[strings.Trim(c.name, "[]")] = value
+		if _, found := values[c.field.Name]; !found {
+			values[c.field.Name] = value
+		}
+	}
+
+	return values, true, nil
+}
+
+// bind_named rewrites the :name placeholders of query into the native placeholders of the engine when params are
+// named (see [named_params]) and returns the positional or named arguments to pass to the driver.
+//
+// SQLITE and MySQL use ? with the values in order of appearance, SQL Server uses @name with [sql.Named] arguments.
+// Placeholders inside quotes, brackets and comments are left untouched as well as :: casts, see [skip_literal]
+func bind_named(engine ENGINE, query string, params []any) (string, []any, error) {
+	values, ok, e := named_params(params)
+	if e != nil {
+		return "", nil, e
+	}
+
+	if !ok {
+		return query, params, nil
+	}
+
+	var b strings.Builder
+	var args []any
+	bound := make(map[string]bool)
+
+	for i := 0; i < len(query); i++ {
+		if end := skip_literal(query, i); end > i {
+			b.WriteString(query[i:end])
+			i = end - 1
+			continue
+		}
+
+		ch := query[i]
+		switch {
+		case ch == ':' && i+1 < len(query) && query[i+1] == ':':
+			b.WriteString("::")
+			i++
+		case ch == ':' && i+1 < len(query) && is_name_start(query[i+1]):
+			end := i + 1
+			for end < len(query) && is_name_char(query[end]) {
+				end++
+			}
+
+			name := query[i+1 : end]
+			value, found := values[name]
+			if !found {
+				return "", nil, fmt.Errorf("named parameter %s has no value", name)
+			}
+
+			switch engine {
+			case SQLServer:
+				b.WriteString("@" + name)
+				if !bound[name] {
+					args = append(args, sql.Named(name, value))
+					bound[name] = true
+				}
+			default:
+				b.WriteByte('?')
+				args = append(args, value)
+			}
+
+			i = end - 1
+		default:
+			b.WriteByte(ch)
+		}
+	}
+
+	return b.String(), args, nil
+}
+
+func is_name_start(ch byte) bool {
+	return ch == '_' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
+
+func is_name_char(ch byte) bool {
+	return is_name_start(ch) || (ch >= '0' && ch <= '9')
+}
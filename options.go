@@ -0,0 +1,206 @@
+package morm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ReadOption configures how [MORM.Read] reads records
+type ReadOption func(*read_options)
+
+type read_options struct {
+	// names of the adjecent struct fields to eager load
+	relations []string
+
+	// model the read target is a projection of
+	source reflect.Type
+
+	// sort order in order of precedence
+	orderby []Order
+
+	limit   int
+	limited bool
+	offset  int
+
+	// records a snapshot of every record read
+	tracked bool
+
+	// err is the error of an invalid option
+	err error
+}
+
+func new_read_options(opts []ReadOption) (read_options, error) {
+	var ro read_options
+	for _, opt := range opts {
+		opt(&ro)
+	}
+	return ro, ro.err
+}
+
+// WithRelations eager loads the adjecent tables of the records read.
+//
+// Relations are the Go field names of untagged struct fields (adjecent tables), for example WithRelations("Email").
+// Each relation is loaded with one extra query for all the records read. The model must have a primary key.
+func WithRelations(relations ...string) ReadOption {
+	return func(ro *read_options) {
+		ro.relations = append(ro.relations, relations...)
+	}
+}
+
+// Tracked records a snapshot of every record read so [MORM.UpdateChanged] can update only the columns that changed.
+//
+// Records are tracked by address, read into a pointer or a slice and pass the same address to UpdateChanged.
+// The model must have a primary key. Snapshots are kept until [MORM.Untrack] is called
+func Tracked() ReadOption {
+	return func(ro *read_options) {
+		ro.tracked = true
+	}
+}
+
+// FromModel reads into a projection of model, a struct declaring a subset of the model fields.
+//
+// Only the columns of the projection are selected. Its fields are matched to the model columns by column name
+// (tag or lowercase field name) or by Go field name and reading fails with [ErrUnmappedField] if any does not map.
+// The table name is derived from model when none is explicit.
+func FromModel(model any) ReadOption {
+	return func(ro *read_options) {
+		ro.source, ro.err = pulltype(model)
+	}
+}
+
+type SortDirection string
+
+const (
+	ASC  SortDirection = "asc"
+	DESC SortDirection = "desc"
+)
+
+// NullsOrder places null values before or after the rest, by default the engine decides
+type NullsOrder int
+
+const (
+	NULLS_DEFAULT NullsOrder = iota
+	NULLS_FIRST
+	NULLS_LAST
+)
+
+// Order is the sort order of a column
+type Order struct {
+	Column    string
+	Direction SortDirection
+	Nulls     NullsOrder
+}
+
+// Asc sorts column in ascending order
+func Asc(column string) Order {
+	return Order{Column: column, Direction: ASC}
+}
+
+// Desc sorts column in descending order
+func Desc(column string) Order {
+	return Order{Column: column, Direction: DESC}
+}
+
+// NullsFirst places null values before the rest
+func (o Order) NullsFirst() Order {
+	o.Nulls = NULLS_FIRST
+	return o
+}
+
+// NullsLast places null values after the rest
+func (o Order) NullsLast() Order {
+	o.Nulls = NULLS_LAST
+	return o
+}
+
+// direction returns the sort direction as ASC or DESC matching it case insensitively, ASC when empty
+func (o Order) direction() (SortDirection, error) {
+	switch SortDirection(strings.ToLower(strings.TrimSpace(string(o.Direction)))) {
+	case "", ASC:
+		return ASC, nil
+	case DESC:
+		return DESC, nil
+	default:
+		return "", fmt.Errorf("order column %s has an invalid direction %q", o.Column, o.Direction)
+	}
+}
+
+// sql returns the order by expressions of the column, the direction and nulls order are checked by [order_by].
+//
+// The nulls order is emulated with a leading case expression since not every engine supports NULLS FIRST/LAST
+func (o Order) sql() []string {
+	expressions := make([]string, 0, 2)
+	switch o.Nulls {
+	case NULLS_FIRST:
+		expressions = append(expressions, fmt.Sprintf("case when %s is null then 0 else 1 end", o.Column))
+	case NULLS_LAST:
+		expressions = append(expressions, fmt.Sprintf("case when %s is null then 1 else 0 end", o.Column))
+	}
+
+	return append(expressions, fmt.Sprintf("%s %s", o.Column, o.Direction))
+}
+
+// order_by returns the order by expressions of orders, every column must be a column of the source model and
+// every direction and nulls order a known one so nothing is spliced into the query as it was passed
+func order_by(orders []Order, source reflect.Type) ([]string, error) {
+	columns, e := model_columns(source)
+	if e != nil {
+		return nil, e
+	}
+
+	var orderby []string
+	for _, o := range orders {
+		found := false
+		for _, c := range columns {
+			if c.name == o.Column || strings.Trim(c.name, "[]") == o.Column {
+				o.Column = c.name
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("%w: order column %s in %s", ErrUnmappedField, o.Column, source.Name())
+		}
+
+		o.Direction, e = o.direction()
+		if e != nil {
+			return nil, e
+		}
+
+		switch o.Nulls {
+		case NULLS_DEFAULT, NULLS_FIRST, NULLS_LAST:
+		default:
+			return nil, fmt.Errorf("order column %s has an invalid nulls order %d", o.Column, o.Nulls)
+		}
+
+		orderby = append(orderby, o.sql()...)
+	}
+
+	return orderby, nil
+}
+
+// OrderBy sorts the records read, orders are applied in the order passed
+//
+//	morm.OrderBy(morm.Desc("weight").NullsLast(), morm.Asc("number"))
+func OrderBy(orders ...Order) ReadOption {
+	return func(ro *read_options) {
+		ro.orderby = append(ro.orderby, orders...)
+	}
+}
+
+// Limit restricts the number of records read to n
+func Limit(n int) ReadOption {
+	return func(ro *read_options) {
+		ro.limit = n
+		ro.limited = true
+	}
+}
+
+// Offset skips the first n records, on SQL Server records are ordered by (select null) when there is no [OrderBy]
+func Offset(n int) ReadOption {
+	return func(ro *read_options) {
+		ro.offset = n
+	}
+}
@@ -0,0 +1,396 @@
+package morm
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// PageRequest describes a page of records read with keyset (cursor) pagination
+type PageRequest struct {
+	// Keys is the sort order of the pages, the last key must be a unique column (primary or unique)
+	Keys []Order
+
+	// Size is the maximum number of records in the page
+	Size int
+
+	// Token is the [Page] Next or Prev token of a previous page, empty for the first page
+	Token string
+}
+
+// Page holds the opaque tokens to request the pages around the one read, a token is empty if there is no page
+type Page struct {
+	Next string
+	Prev string
+}
+
+const (
+	page_forward  = "next"
+	page_backward = "prev"
+)
+
+// page_cursor is the content of a page token
+type page_cursor struct {
+	Direction string            `json:"d"`
+	Keys      []string          `json:"k"`
+	Values    []json.RawMessage `json:"v"`
+}
+
+// SetPageSecret sets the secret page tokens are signed with.
+//
+// Tokens are tamper evident, a token that was altered or signed with another secret is rejected with
+// [ErrInvalidPageToken]. When no secret is set a random one is generated so tokens are only valid for the life of
+// the client
+func (m *MORM) SetPageSecret(secret []byte) {
+	if m == nil {
+		return
+	}
+
+	m.pagesecretmu.Lock()
+	defer m.pagesecretmu.Unlock()
+	m.pagesecret = append([]byte(nil), secret...)
+}
+
+// ReadPage reads a page of records into model, a pointer to a slice, using keyset pagination.
+//
+// Instead of an offset the page starts after (or before) the boundary record encoded in req.Token so reading a page
+// is as fast as reading the first one. The returned [Page] has the tokens of the next and previous pages.
+func (m *MORM) ReadPage(model any, filters *Filter, tablename string, req PageRequest, opts ...ReadOption) (Page, error) {
+	if m == nil {
+		return Page{}, ErrDefaultClientIsNil
+	}
+
+	var page Page
+
+	ro, e := new_read_options(opts)
+	if e != nil {
+		return page, e
+	}
+
+	if len(ro.orderby) > 0 || ro.limited || ro.offset > 0 {
+		return page, errors.New("order, limit and offset are set by the page request")
+	}
+
+	if req.Size <= 0 {
+		return page, fmt.Errorf("page size must be greater than 0 got %d", req.Size)
+	}
+
+	if len(req.Keys) == 0 {
+		return page, errors.New("page request has no keys")
+	}
+
+	v := reflect.ValueOf(model)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return page, errors.New("page target must be a non nil pointer to a slice")
+	}
+	v = v.Elem()
+
+	t, e := record_type(model)
+	if e != nil {
+		return page, e
+	}
+
+	source := t
+	columns, e := model_columns(t)
+	if e != nil {
+		return page, e
+	}
+
+	if ro.source != nil {
+		source = ro.source
+
+		columns, e = project_columns(t, source)
+		if e != nil {
+			return page, e
+		}
+	}
+
+	keycolumns, e := page_key_columns(req.Keys, columns)
+	if e != nil {
+		return page, e
+	}
+
+	if !keycolumns[len(keycolumns)-1].tag.IsUnique() {
+		return page, fmt.Errorf("last page key %s must be a unique column of %s", req.Keys[len(req.Keys)-1].Column, source.Name())
+	}
+
+	secret, e := m.page_secret()
+	if e != nil {
+		return page, e
+	}
+
+	direction := page_forward
+	var cursor *page_cursor
+	if req.Token != "" {
+		c, e := decode_page_token(req.Token, secret, req.Keys)
+		if e != nil {
+			return page, e
+		}
+		cursor = &c
+		direction = c.Direction
+	}
+
+	// note: previous pages are read in reverse order and flipped back after reading
+	orders := make([]Order, len(req.Keys))
+	for i, k := range req.Keys {
+		// note: the directions were checked with the key columns
+		orders[i] = k
+		orders[i].Direction, _ = k.direction()
+		if direction == page_backward {
+			orders[i].Direction = flip_direction(orders[i].Direction)
+		}
+	}
+
+	// note: the cursor condition is added to a copy so the caller filters are not changed
+	pagefilters := filters
+	if cursor != nil {
+		var f Filter
+		if filters != nil {
+			f = filters.clone()
+		} else {
+			f = NewFilter()
+		}
+
+		e := keyset_group(f.Group(), orders, keycolumns, cursor.Values)
+		if e != nil {
+			return page, e
+		}
+		pagefilters = &f
+	}
+
+	ro.orderby = orders
+	ro.limit = req.Size + 1
+	ro.limited = true
+
+	e = read(model, pagefilters, m, tablename, ro)
+	if e != nil {
+		return page, e
+	}
+
+	more := v.Len() > req.Size
+	if more {
+		v.Set(v.Slice(0, req.Size))
+	}
+
+	if direction == page_backward {
+		reverse_slice(v)
+	}
+
+	if v.Len() == 0 {
+		return page, nil
+	}
+
+	first, last := v.Index(0), v.Index(v.Len()-1)
+	if first.Kind() == reflect.Pointer {
+		first, last = first.Elem(), last.Elem()
+	}
+
+	hasnext := more || direction == page_backward
+	hasprev := (more && direction == page_backward) || (cursor != nil && direction == page_forward)
+
+	if hasnext {
+		page.Next, e = encode_page_token(page_forward, req.Keys, keycolumns, last, secret)
+		if e != nil {
+			return page, e
+		}
+	}
+
+	if hasprev {
+		page.Prev, e = encode_page_token(page_backward, req.Keys, keycolumns, first, secret)
+		if e != nil {
+			return page, e
+		}
+	}
+
+	return page, nil
+}
+
+// page_secret returns the secret page tokens are signed with generating one if there is none.
+// Concurrent reads share the secret generated by the first one
+func (m *MORM) page_secret() ([]byte, error) {
+	m.pagesecretmu.Lock()
+	defer m.pagesecretmu.Unlock()
+
+	if len(m.pagesecret) > 0 {
+		return m.pagesecret, nil
+	}
+
+	secret := make([]byte, 32)
+	_, e := rand.Read(secret)
+	if e != nil {
+		return nil, e
+	}
+
+	m.pagesecret = secret
+	return secret, nil
+}
+
+// page_key_columns returns the read columns of the page keys in the same order
+func page_key_columns(keys []Order, columns []column) ([]column, error) {
+	byname := make(map[string]column, len(columns))
+	for _, c := range columns {
+		byname[c.name] = c
+	}
+
+	keycolumns := make([]column, 0, len(keys))
+	for _, k := range keys {
+		c, found := byname[k.Column]
+		if !found {
+			return nil, fmt.Errorf("%w: page key %s is not a column read", ErrUnmappedField, k.Column)
+		}
+
+		if k.Nulls != NULLS_DEFAULT {
+			return nil, fmt.Errorf("page key %s can not have a nulls order", k.Column)
+		}
+
+		if _, e := k.direction(); e != nil {
+			return nil, e
+		}
+
+		keycolumns = append(keycolumns, c)
+	}
+
+	return keycolumns, nil
+}
+
+// keyset_group adds to the group the condition of the records after the cursor values in the given orders.
+//
+// Tuple comparison is not supported by every engine so it is expanded
+// (a > x) or (a = x and b > y) or (a = x and b = y and c > z) ...
+func keyset_group(g *FilterGroup, orders []Order, keycolumns []column, raw []json.RawMessage) error {
+	if len(raw) != len(keycolumns) {
+		return ErrInvalidPageToken
+	}
+
+	values := make([]any, len(raw))
+	for i, c := range keycolumns {
+		value := reflect.New(c.field.Type)
+		e := json.Unmarshal(raw[i], value.Interface())
+		if e != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidPageToken, e.Error())
+		}
+
+		values[i] = stored_value(value.Elem(), c)
+		if values[i] == nil {
+			return fmt.Errorf("page key %s can not be null", c.name)
+		}
+	}
+
+	for i := range orders {
+		comparison := GREATER
+		if orders[i].Direction == DESC {
+			comparison = LESS_THAN
+		}
+
+		for j := 0; j < i; j++ {
+			if j == 0 {
+				g.Or(keycolumns[j].name, EQUAL, values[j])
+				continue
+			}
+			g.And(keycolumns[j].name, EQUAL, values[j])
+		}
+
+		g.And(keycolumns[i].name, comparison, values[i])
+	}
+
+	return nil
+}
+
+// encode_page_token encodes the key values of record into a signed token
+func encode_page_token(direction string, keys []Order, keycolumns []column, record reflect.Value, secret []byte) (string, error) {
+	cursor := page_cursor{Direction: direction, Keys: page_keys(keys)}
+	for _, c := range keycolumns {
+		raw, e := json.Marshal(record.FieldByIndex(c.index).Interface())
+		if e != nil {
+			return "", e
+		}
+		cursor.Values = append(cursor.Values, raw)
+	}
+
+	payload, e := json.Marshal(cursor)
+	if e != nil {
+		return "", e
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+
+	encoding := base64.RawURLEncoding
+	return encoding.EncodeToString(payload) + "." + encoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// decode_page_token verifies the token signature and that it was issued for the same keys
+func decode_page_token(token string, secret []byte, keys []Order) (page_cursor, error) {
+	var cursor page_cursor
+
+	encodedpayload, encodedsignature, found := strings.Cut(token, ".")
+	if !found {
+		return cursor, ErrInvalidPageToken
+	}
+
+	encoding := base64.RawURLEncoding
+	payload, e := encoding.DecodeString(encodedpayload)
+	if e != nil {
+		return cursor, ErrInvalidPageToken
+	}
+
+	signature, e := encoding.DecodeString(encodedsignature)
+	if e != nil {
+		return cursor, ErrInvalidPageToken
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return cursor, ErrInvalidPageToken
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.DisallowUnknownFields()
+	e = decoder.Decode(&cursor)
+	if e != nil {
+		return cursor, ErrInvalidPageToken
+	}
+
+	if cursor.Direction != page_forward && cursor.Direction != page_backward {
+		return cursor, ErrInvalidPageToken
+	}
+
+	if strings.Join(cursor.Keys, ",") != strings.Join(page_keys(keys), ",") {
+		return cursor, fmt.Errorf("%w: token was issued for different keys", ErrInvalidPageToken)
+	}
+
+	return cursor, nil
+}
+
+// page_keys returns the keys as column and direction strings
+func page_keys(keys []Order) []string {
+	s := make([]string, len(keys))
+	for i, k := range keys {
+		direction, _ := k.direction()
+		s[i] = fmt.Sprintf("%s %s", k.Column, direction)
+	}
+	return s
+}
+
+func flip_direction(d SortDirection) SortDirection {
+	if d == DESC {
+		return ASC
+	}
+	return DESC
+}
+
+// reverse_slice reverses the elements of a slice value in place
+func reverse_slice(v reflect.Value) {
+	swap := reflect.Swapper(v.Interface())
+	for i, j := 0, v.Len()-1; i < j; i, j = i+1, j-1 {
+		swap(i, j)
+	}
+}
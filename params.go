@@ -0,0 +1,205 @@
+package morm
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// statement is a query with ? placeholders and the args bound to them in order
+type statement struct {
+	query string
+	args  []any
+}
+
+// sql_executor is a [sql.DB] or a [sql.Tx]
+type sql_executor interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// exec executes the statement with the placeholders of engine and records it in the query history
+func (s statement) exec(db sql_executor, engine ENGINE) (sql.Result, error) {
+	_queryHistory = append(_queryHistory, s.debug())
+	return db.Exec(s.native(engine), s.args...)
+}
+
+// query_rows executes the statement that returns rows with the placeholders of engine and records it in the query history
+func (s statement) query_rows(db sql_executor, engine ENGINE) (*sql.Rows, error) {
+	_queryHistory = append(_queryHistory, s.debug())
+	return db.Query(s.native(engine), s.args...)
+}
+
+// query_row executes the statement that returns one row with the placeholders of engine and records it in the
+// query history
+func (s statement) query_row(db sql_executor, engine ENGINE) *sql.Row {
+	_queryHistory = append(_queryHistory, s.debug())
+	return db.QueryRow(s.native(engine), s.args...)
+}
+
+// native returns the query with the placeholders of the engine, SQL Server uses ordinal @p1, @p2...
+func (s statement) native(engine ENGINE) string {
+	if engine != SQLServer || len(s.args) == 0 {
+		return s.query
+	}
+
+	n := 0
+	return replace_placeholders(s.query, func() string {
+		n++
+		return "@p" + strconv.Itoa(n)
+	})
+}
+
+// debug returns the query with the args rendered as literals, for the query history and error messages only
+func (s statement) debug() string {
+	n := 0
+	return replace_placeholders(s.query, func() string {
+		if n >= len(s.args) {
+			return "?"
+		}
+
+		arg := s.args[n]
+		n++
+
+		if b, ok := arg.([]byte); ok {
+			return "x'" + hex.EncodeToString(b) + "'"
+		}
+
+		literal, e := anytostr(arg)
+		if e != nil {
+			return fmt.Sprintf("%v", arg)
+		}
+		return literal
+	})
+}
+
+// replace_placeholders replaces every ? placeholder of query with the value of next.
+// Placeholders inside quotes, brackets and comments are left untouched, see [skip_literal]
+func replace_placeholders(query string, next func() string) string {
+	var b strings.Builder
+	for i := 0; i < len(query); i++ {
+		if end := skip_literal(query, i); end > i {
+			b.WriteString(query[i:end])
+			i = end - 1
+			continue
+		}
+
+		if query[i] == '?' {
+			b.WriteString(next())
+			continue
+		}
+		b.WriteByte(query[i])
+	}
+	return b.String()
+}
+
+// skip_literal returns the end of the quoted string, quoted identifier or comment starting at i in query, i when
+// there is none. [replace_placeholders] and [bind_named] both tokenize queries with it.
+//
+// Doubled quotes inside a literal are escapes, unterminated literals and comments run to the end of the query
+func skip_literal(query string, i int) int {
+	switch ch := query[i]; {
+	case ch == '\'' || ch == '"' || ch == '`' || ch == '[':
+		closing := ch
+		if ch == '[' {
+			closing = ']'
+		}
+
+		for end := i + 1; end < len(query); end++ {
+			if query[end] != closing {
+				continue
+			}
+
+			if end+1 < len(query) && query[end+1] == closing {
+				end++
+				continue
+			}
+			return end + 1
+		}
+		return len(query)
+	case strings.HasPrefix(query[i:], "--"):
+		end := strings.IndexByte(query[i:], '\n')
+		if end == -1 {
+			return len(query)
+		}
+		return i + end + 1
+	case strings.HasPrefix(query[i:], "/*"):
+		end := strings.Index(query[i+2:], "*/")
+		if end == -1 {
+			return len(query)
+		}
+		return i + 2 + end + 2
+	}
+
+	return i
+}
+
+// placeholders returns n comma separated ? placeholders
+func placeholders(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strings.Repeat("?, ", n-1) + "?"
+}
+
+// field_arg returns the value of a field as the driver argument it is stored as.
+//
+// Booleans are stored as 1 or 0 and time as the tag field type declares, see [stored_value]
+func field_arg(val reflect.Value, fieldType reflect.Type, tag MormTag) (any, error) {
+	switch fieldType.Kind() {
+	case reflect.String:
+		return val.String(), nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int:
+		return val.Int(), nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint:
+		return val.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return val.Float(), nil
+	case reflect.Bool:
+		if val.Bool() {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	case reflect.Pointer:
+		if val.IsNil() {
+			return nil, nil
+		}
+		return field_arg(val.Elem(), fieldType.Elem(), tag)
+	case reflect.Slice:
+		if fieldType.Elem().Kind() == reflect.Uint8 {
+			return val.Bytes(), nil
+		}
+	case reflect.Struct:
+		if istimetype(fieldType) {
+			return stored_value(val, column{tag: tag}), nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: unable to bind %s", ErrUnsupportedType, fieldType)
+}
+
+// filter_arg returns a filter value as a driver argument, time is formatted as it is stored by default
+func filter_arg(val any) (any, error) {
+	if val == nil {
+		return nil, nil
+	}
+
+	v := reflect.ValueOf(val)
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+
+	if istimetype(v.Type()) {
+		return v.Interface().(time.Time).Format(time.DateTime), nil
+	}
+
+	return field_arg(v, v.Type(), MormTag{})
+}
@@ -0,0 +1,245 @@
+package morm
+
+import (
+	"bufio"
+	"database/sql"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+)
+
+// any_engine is the key of queries without an engine variant
+const any_engine ENGINE = 0
+
+// LoadQueries parses the .sql files in fsys matching patterns and registers their queries on the client.
+//
+// Every query starts with a name header and can be followed by an engine header for engine specific variants,
+// the variant of the client engine is preferred over the query without engine. Works with [embed.FS] so queries
+// ship inside the binary.
+//
+//	-- name: GetActiveUsers
+//	select id, email from users where active = 1 limit :limit;
+//
+//	-- name: GetActiveUsers
+//	-- engine: SQLServer
+//	select top(:limit) id, email from users where active = 1;
+func (m *MORM) LoadQueries(fsys fs.FS, patterns ...string) error {
+	if m == nil {
+		return ErrDefaultClientIsNil
+	}
+
+	if len(patterns) == 0 {
+		patterns = []string{"*.sql"}
+	}
+
+	for _, pattern := range patterns {
+		files, e := fs.Glob(fsys, pattern)
+		if e != nil {
+			return e
+		}
+
+		for _, file := range files {
+			f, e := fsys.Open(file)
+			if e != nil {
+				return e
+			}
+
+			e = m.parse_queries(f, file)
+			f.Close()
+			if e != nil {
+				return e
+			}
+		}
+	}
+
+	return nil
+}
+
+// RegisterQuery registers a query by name, engine 0 registers it for every engine
+func (m *MORM) RegisterQuery(name string, engine ENGINE, query string) error {
+	if m == nil {
+		return ErrDefaultClientIsNil
+	}
+
+	if name == "" {
+		return fmt.Errorf("query name is <nil>")
+	}
+
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return fmt.Errorf("query %s is empty", name)
+	}
+
+	if m.queries == nil {
+		m.queries = make(map[string]map[ENGINE]string)
+	}
+
+	if m.queries[name] == nil {
+		m.queries[name] = make(map[ENGINE]string)
+	}
+
+	if _, found := m.queries[name][engine]; found {
+		return fmt.Errorf("query %s is already registered for engine %s", name, engine)
+	}
+
+	m.queries[name][engine] = query
+	return nil
+}
+
+// NamedQuery returns the registered query for the client engine
+func (m *MORM) NamedQuery(name string) (string, error) {
+	if m == nil {
+		return "", ErrDefaultClientIsNil
+	}
+
+	variants, found := m.queries[name]
+	if !found {
+		return "", fmt.Errorf("query %s is not registered", name)
+	}
+
+	if query, found := variants[m.engine]; found {
+		return query, nil
+	}
+
+	if query, found := variants[any_engine]; found {
+		return query, nil
+	}
+
+	return "", fmt.Errorf("query %s is not registered for engine %s", name, m.engine)
+}
+
+// ExecNamed executes a registered query, params can be named see [MORM.Exec]
+func (m *MORM) ExecNamed(name string, params ...any) (sql.Result, error) {
+	if m == nil {
+		return nil, ErrDefaultClientIsNil
+	}
+
+	query, e := m.NamedQuery(name)
+	if e != nil {
+		return nil, e
+	}
+	return m.Exec(query, params...)
+}
+
+// QueryNamed executes a registered query that returns rows, params can be named see [MORM.Exec]
+func (m *MORM) QueryNamed(name string, params ...any) (*sql.Rows, error) {
+	if m == nil {
+		return nil, ErrDefaultClientIsNil
+	}
+
+	query, e := m.NamedQuery(name)
+	if e != nil {
+		return nil, e
+	}
+	return m.Query(query, params...)
+}
+
+// QueryNamedInto executes a registered query and scans the result into dest, see [MORM.QueryInto]
+func (m *MORM) QueryNamedInto(dest any, name string, params ...any) error {
+	if m == nil {
+		return ErrDefaultClientIsNil
+	}
+
+	query, e := m.NamedQuery(name)
+	if e != nil {
+		return e
+	}
+	return m.QueryInto(dest, query, params...)
+}
+
+// parse_queries reads the queries of a .sql file and registers them, file is used for error messages
+func (m *MORM) parse_queries(r io.Reader, file string) error {
+	var name string
+	var engine ENGINE
+	var body []string
+	var line_number, start int
+
+	flush := func() error {
+		if name == "" {
+			return nil
+		}
+
+		e := m.RegisterQuery(name, engine, strings.Join(body, "\n"))
+		if e != nil {
+			return fmt.Errorf("%s:%d %w", file, start, e)
+		}
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line_number++
+		line := scanner.Text()
+
+		if value, found := query_header(line, "name"); found {
+			e := flush()
+			if e != nil {
+				return e
+			}
+
+			if value == "" {
+				return fmt.Errorf("%s:%d query name is empty", file, line_number)
+			}
+
+			name, engine, body, start = value, any_engine, nil, line_number
+			continue
+		}
+
+		// note: the engine header is only valid right after the name header
+		if value, found := query_header(line, "engine"); found && name != "" && len(body) == 0 {
+			parsed, e := parse_engine(value)
+			if e != nil {
+				return fmt.Errorf("%s:%d %w", file, line_number, e)
+			}
+			engine = parsed
+			continue
+		}
+
+		if name == "" {
+			trimmed := strings.TrimSpace(line)
+			if trimmed != "" && !strings.HasPrefix(trimmed, "--") {
+				return fmt.Errorf("%s:%d sql found before the first query name", file, line_number)
+			}
+			continue
+		}
+
+		if len(body) == 0 && strings.TrimSpace(line) == "" {
+			continue
+		}
+		body = append(body, line)
+	}
+
+	if e := scanner.Err(); e != nil {
+		return e
+	}
+
+	return flush()
+}
+
+// query_header returns the value of a -- key: value comment line
+func query_header(line, key string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "--") {
+		return "", false
+	}
+
+	trimmed = strings.TrimSpace(strings.TrimPrefix(trimmed, "--"))
+	k, value, found := strings.Cut(trimmed, ":")
+	if !found || !strings.EqualFold(strings.TrimSpace(k), key) {
+		return "", false
+	}
+
+	return strings.TrimSpace(value), true
+}
+
+// parse_engine returns the engine of its name as in [ENGINE.String], case insensitive
+func parse_engine(name string) (ENGINE, error) {
+	for _, engine := range []ENGINE{SQLITE, SQLServer, POSTGRESS, MySQL} {
+		if strings.EqualFold(engine.String(), name) {
+			return engine, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown engine %s", name)
+}
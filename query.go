@@ -0,0 +1,164 @@
+package morm
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// QueryInto executes an arbitrary query and scans the result into dest.
+//
+// dest is a pointer to a struct, where the first row is scanned and [ErrNotFound] is returned if there is none, or a
+// pointer to a slice of structs (or struct pointers). Result columns are matched to fields by morm tag name or
+// lowercase field name, columns that don't match any field are ignored (see [MORM.QueryIntoStrict])
+func (m *MORM) QueryInto(dest any, query string, params ...any) error {
+	if m == nil {
+		return ErrDefaultClientIsNil
+	}
+
+	return query_into(dest, m, false, query, params...)
+}
+
+// QueryIntoStrict is [MORM.QueryInto] but it fails with [ErrUnmappedField] if a result column does not match a field
+func (m *MORM) QueryIntoStrict(dest any, query string, params ...any) error {
+	if m == nil {
+		return ErrDefaultClientIsNil
+	}
+
+	return query_into(dest, m, true, query, params...)
+}
+
+func query_into(dest any, m *MORM, strict bool, query string, params ...any) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return ErrReadTargetNotPointer
+	}
+	v = v.Elem()
+
+	t := v.Type()
+	is_container := false
+	elem_is_pointer := false
+	if t.Kind() == reflect.Slice {
+		is_container = true
+		t = t.Elem()
+		if t.Kind() == reflect.Pointer {
+			elem_is_pointer = true
+			t = t.Elem()
+		}
+	}
+
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: unable to scan into %s, expected a struct or a slice of structs", ErrUnsupportedType, v.Type())
+	}
+
+	rows, e := m.Query(query, params...)
+	if e != nil {
+		return e
+	}
+	defer rows.Close()
+
+	_queryHistory = append(_queryHistory, query)
+
+	names, e := rows.Columns()
+	if e != nil {
+		return e
+	}
+
+	columns, e := result_columns(t, names, strict)
+	if e != nil {
+		return e
+	}
+
+	if !is_container {
+		if !rows.Next() {
+			if e := rows.Err(); e != nil {
+				return e
+			}
+			return ErrNotFound
+		}
+
+		e := scan_result(rows, v, columns)
+		if e != nil {
+			return e
+		}
+		return rows.Err()
+	}
+
+	records := reflect.MakeSlice(v.Type(), 0, 0)
+	for rows.Next() {
+		record := reflect.New(t)
+		e := scan_result(rows, record.Elem(), columns)
+		if e != nil {
+			return e
+		}
+
+		if elem_is_pointer {
+			records = reflect.Append(records, record)
+		} else {
+			records = reflect.Append(records, record.Elem())
+		}
+	}
+
+	if e := rows.Err(); e != nil {
+		return e
+	}
+
+	v.Set(records)
+	return nil
+}
+
+// result_columns matches the result column names with the columns of t in the same order.
+//
+// A column is matched by its morm name and then by the lowercase field name. Unmatched columns are nil unless
+// strict is set in which case an [ErrUnmappedField] error is returned
+func result_columns(t reflect.Type, names []string, strict bool) ([]*column, error) {
+	modelcolumns, e := model_columns(t)
+	if e != nil {
+		return nil, e
+	}
+
+	byname := make(map[string]*column, len(modelcolumns))
+	byfield := make(map[string]*column, len(modelcolumns))
+	for i := range modelcolumns {
+		c := &modelcolumns[i]
+		byname[strings.ToLower(strings.Trim(c.name, "[]"))] = c
+		if _, found := byfield[strings.ToLower(c.field.Name)]; !found {
+			byfield[strings.ToLower(c.field.Name)] = c
+		}
+	}
+
+	columns := make([]*column, len(names))
+	for i, name := range names {
+		name = strings.ToLower(name)
+
+		if c, found := byname[name]; found {
+			columns[i] = c
+			continue
+		}
+
+		if c, found := byfield[name]; found {
+			columns[i] = c
+			continue
+		}
+
+		if strict {
+			return nil, fmt.Errorf("%w: result column %s has no field in %s", ErrUnmappedField, name, t.Name())
+		}
+	}
+
+	return columns, nil
+}
+
+// scan_result scans the current row into v, columns without a field are discarded
+func scan_result(rows *sql.Rows, v reflect.Value, columns []*column) error {
+	dest := make([]any, len(columns))
+	for i, c := range columns {
+		if c == nil {
+			dest[i] = new(any)
+			continue
+		}
+		dest[i] = &field_scanner{dst: v.FieldByIndex(c.index), tag: c.tag}
+	}
+	return rows.Scan(dest...)
+}
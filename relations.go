@@ -0,0 +1,193 @@
+package morm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// adjecent_link is the column linking the rows of an adjecent table to their parent record
+type adjecent_link struct {
+	// name of the column in the adjecent table
+	name string
+
+	// sql type of the column, the same as the parent primary key
+	sqltype string
+
+	// value of the parent primary key bound as argument
+	value any
+}
+
+// primary_key returns the column tagged as primary key if any
+func primary_key(t reflect.Type) (column, bool) {
+	// note: the columns of a model with an invalid tag fail every other statement so there is no key to return
+	columns, e := model_columns(t)
+	if e != nil {
+		return column{}, false
+	}
+
+	for _, c := range columns {
+		if c.tag.IsPrimary() {
+			return c, true
+		}
+	}
+	return column{}, false
+}
+
+// link_name returns the name of the column in adjecent tables that references the parent primary key.
+//
+// The lowercase parent struct name and the primary key column name, for example user_id
+func link_name(parent reflect.Type, pk column) string {
+	return fmt.Sprintf("%s_%s", strings.ToLower(parent.Name()), strings.Trim(pk.name, "[]"))
+}
+
+// new_adjecent_link returns the link adjecent tables of parent use or nil if parent has no primary key.
+//
+// If v is valid the parent primary key value is formatted into the link
+func new_adjecent_link(parent reflect.Type, v reflect.Value) (*adjecent_link, error) {
+	pk, ok := primary_key(parent)
+	if !ok {
+		return nil, nil
+	}
+
+	link := adjecent_link{name: link_name(parent, pk), sqltype: pk.tag.fieldtype}
+	if v.IsValid() {
+		value, e := field_arg(v.FieldByIndex(pk.index), pk.field.Type, pk.tag)
+		if e != nil {
+			return nil, field_error(parent, pk.field, e)
+		}
+		link.value = value
+	}
+
+	return &link, nil
+}
+
+// load_relations eager loads the adjecent tables of the parent records for every relation.
+//
+// Each relation is the name of an untagged struct field of the parent and is loaded with a single query
+// for all parents.
+func load_relations(parents []reflect.Value, t reflect.Type, relations []string, m *MORM) error {
+	if len(relations) == 0 || len(parents) == 0 {
+		return nil
+	}
+
+	pk, ok := primary_key(t)
+	if !ok {
+		return fmt.Errorf("%w: %s relations can not be loaded", ErrNoPrimaryKey, t.Name())
+	}
+
+	// note: parents are grouped by primary key so every adjecent row can be matched with its parents
+	keys := make([]any, 0, len(parents))
+	byid := make(map[string][]reflect.Value)
+	for _, parent := range parents {
+		pkvalue := parent.FieldByIndex(pk.index)
+		key, e := tostring(pkvalue, pk.field.Type, pk.tag)
+		if e != nil {
+			return e
+		}
+
+		if _, found := byid[key]; !found {
+			keys = append(keys, pkvalue.Interface())
+		}
+		byid[key] = append(byid[key], parent)
+	}
+
+	for _, relation := range relations {
+		field, ok := t.FieldByName(relation)
+		if !ok {
+			return fmt.Errorf("%s relation not found in %s", relation, t.Name())
+		}
+
+		if tag, _ := gettag(field); !tag.IsEmpty() || field.Type.Kind() != reflect.Struct {
+			return fmt.Errorf("%s is not an adjecent table of %s", relation, t.Name())
+		}
+
+		e := load_relation(field, t, pk, keys, byid, m)
+		if e != nil {
+			return err_wrap(e, fmt.Sprintf("unable to load relation %s", relation))
+		}
+	}
+
+	return nil
+}
+
+// load_relation reads the adjecent rows of field for every parent key and sets them into the parents.
+//
+// Keys are bound in chunks so the parameter limits of the engine are respected
+func load_relation(field reflect.StructField, t reflect.Type, pk column, keys []any, byid map[string][]reflect.Value, m *MORM) error {
+	size := chunk_size(m.engine, 1, bulk_options{})
+	for start := 0; start < len(keys); start += size {
+		e := load_relation_chunk(field, t, pk, keys[start:min(start+size, len(keys))], byid, m)
+		if e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+func load_relation_chunk(field reflect.StructField, t reflect.Type, pk column, keys []any, byid map[string][]reflect.Value, m *MORM) error {
+	columns, e := model_columns(field.Type)
+	if e != nil {
+		return e
+	}
+	link := link_name(t, pk)
+
+	filters := NewFilter()
+	filters.And(link, IN, keys)
+	where_clause, args, e := filters.WhereArgs()
+	if e != nil {
+		return e
+	}
+
+	selected_fields := append(column_names(columns), link)
+	query := fmt.Sprintf("select %s\nfrom %s\n%s;", strings.Join(selected_fields, ", "), default_tablename(field.Type), where_clause)
+
+	if !m.connected {
+		e := m.connect()
+		if e != nil {
+			return e
+		}
+	}
+
+	switch m.engine {
+	case SQLServer:
+		usedb, e := mssql_use_db(m)
+		if e != nil {
+			return e
+		}
+		query = usedb + query
+	}
+
+	rows, e := statement{query: query, args: args}.query_rows(m.db, m.engine)
+	if e != nil {
+		return e
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		record := reflect.New(field.Type).Elem()
+		linkvalue := reflect.New(pk.field.Type).Elem()
+
+		dest := make([]any, 0, len(columns)+1)
+		for _, c := range columns {
+			dest = append(dest, &field_scanner{dst: record.FieldByIndex(c.index), tag: c.tag})
+		}
+		dest = append(dest, &field_scanner{dst: linkvalue, tag: pk.tag})
+
+		e := rows.Scan(dest...)
+		if e != nil {
+			return e
+		}
+
+		key, e := tostring(linkvalue, pk.field.Type, pk.tag)
+		if e != nil {
+			return e
+		}
+
+		for _, parent := range byid[key] {
+			parent.FieldByIndex(field.Index).Set(record)
+		}
+	}
+
+	return rows.Err()
+}
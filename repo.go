@@ -0,0 +1,112 @@
+package morm
+
+import (
+	"fmt"
+	"iter"
+	"reflect"
+)
+
+// Repo is a typed access to the table of the model T.
+//
+// Every operation is bound to the same [MORM] client and table so records of the wrong type or table can not be
+// mixed up at compile time
+type Repo[T any] struct {
+	m         *MORM
+	tablename string
+}
+
+// NewRepo returns a [Repo] of T, a struct, in tablename using m.
+// If tablename is empty the table name is derived from T like [MORM.CreateTable] does
+func NewRepo[T any](m *MORM, tablename string) (*Repo[T], error) {
+	if m == nil {
+		return nil, ErrDefaultClientIsNil
+	}
+
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("repo model must be a struct got %s", t)
+	}
+
+	if tablename == "" {
+		tablename = default_tablename(t)
+	}
+
+	return &Repo[T]{m: m, tablename: tablename}, nil
+}
+
+// Table returns the name of the table the repo is bound to
+func (r *Repo[T]) Table() string { return r.tablename }
+
+// Create creates the table of T if it does not exists
+func (r *Repo[T]) Create() error {
+	var model T
+	return r.m.CreateTable(model, r.tablename)
+}
+
+// Insert creates a new record
+func (r *Repo[T]) Insert(record *T) error {
+	return insert(record, r.tablename, r.m)
+}
+
+// InsertMany inserts records in chunks in a single transaction, see [InsertMany]
+func (r *Repo[T]) InsertMany(records []T, opts ...BulkOption) Result {
+	return InsertMany(r.m, records, r.tablename, opts...)
+}
+
+// Get returns the record with the primary key id, see [MORM.FindByID]
+func (r *Repo[T]) Get(id any, opts ...ReadOption) (T, error) {
+	var record T
+	e := r.m.FindByID(&record, id, r.tablename, opts...)
+	return record, e
+}
+
+// First returns the first record matching filters, see [MORM.First]
+func (r *Repo[T]) First(filters *Filter, opts ...ReadOption) (T, error) {
+	var record T
+	e := r.m.First(&record, filters, r.tablename, opts...)
+	return record, e
+}
+
+// List returns every record matching filters, filters can be nil
+func (r *Repo[T]) List(filters *Filter, opts ...ReadOption) ([]T, error) {
+	var records []T
+	e := r.m.Read(&records, filters, r.tablename, opts...)
+	return records, e
+}
+
+// Iter streams the records matching filters, see [ReadIter]
+func (r *Repo[T]) Iter(filters *Filter, opts ...ReadOption) iter.Seq2[T, error] {
+	return ReadIter[T](r.m, filters, r.tablename, opts...)
+}
+
+// Update makes changes to the fields of the records matching filters
+func (r *Repo[T]) Update(record *T, filters *Filter, fields ...string) Result {
+	return update(record, r.tablename, filters, r.m, fields...)
+}
+
+// UpdateExpr updates the records matching filters with expressions, see [MORM.UpdateExpr]
+func (r *Repo[T]) UpdateExpr(filters *Filter, assignments ...Assignment) Result {
+	return r.m.UpdateExprByName(r.tablename, filters, assignments...)
+}
+
+// UpdateMany updates every record by primary key with its own values, see [UpdateMany]
+func (r *Repo[T]) UpdateMany(records []T, fields []string, opts ...BulkOption) Result {
+	return UpdateMany(r.m, records, r.tablename, fields, opts...)
+}
+
+// Delete deletes the records matching filters, filters are required
+func (r *Repo[T]) Delete(filters *Filter) Result {
+	return delete_records(r.tablename, filters, r.m)
+}
+
+// Count returns the number of records matching filters
+func (r *Repo[T]) Count(filters *Filter) (int64, error) {
+	var model T
+	return r.m.Count(model, filters, r.tablename)
+}
+
+// Exists checks if any record matches filters
+func (r *Repo[T]) Exists(filters *Filter) (bool, error) {
+	var model T
+	return r.m.Exists(model, filters, r.tablename)
+}
@@ -3,10 +3,13 @@ package morm
 type Result struct {
 	Error        error
 	RowsAffected int64
+
+	// Tables are the rows affected by table for operations writing to several tables
+	Tables map[string]int64
 }
 
 func new_result(e error, rows int64) Result {
-	return Result{e, rows}
+	return Result{Error: e, RowsAffected: rows}
 }
 
 func error_result(e error) Result {
@@ -0,0 +1,366 @@
+package morm
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// unixEpochJD is the julian day of the unix epoch, time stored as real is a julian day number
+const unixEpochJD = 2440587.5
+
+// column is the in memory representation of a model field persisted as a column
+type column struct {
+	// name of the column in the table
+	name string
+
+	// index path to the struct field holding the value (see [reflect.Value.FieldByIndex])
+	index []int
+
+	field reflect.StructField
+	tag   MormTag
+}
+
+// model_columns resolves the columns a model is persisted to following the same naming rules
+// [MORM.CreateTable] and [MORM.Insert] use.
+//
+// Untagged struct fields and container fields are skipped since they live in adjecent tables.
+// Flattened structures are expanded into their prefixed columns
+func model_columns(t reflect.Type) ([]column, error) {
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %s, expected a struct", ErrUnsupportedType, t)
+	}
+	return struct_columns(t, nil, false, make(map[string]struct{}))
+}
+
+// struct_columns collects the columns of t, parent is the index path to t from the model root.
+//
+// flattened columns are prefixed with the lowercase struct name as [extract_columns] does and renamed when
+// they collide with a column seen before
+func struct_columns(t reflect.Type, parent []int, flattened bool, seenfields map[string]struct{}) ([]column, error) {
+	var columns []column
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		mormtag, e := gettag(field)
+		if e != nil {
+			return nil, field_error(t, field, e)
+		}
+		index := append(append([]int{}, parent...), i)
+
+		if mormtag.IsEmpty() {
+			switch field.Type.Kind() {
+			case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map:
+				continue
+			}
+		} else if mormtag.IsDirective() {
+			switch mormtag.tag {
+			case IgnoreDirective:
+				continue
+			case FlattenDirective:
+				flattenedcolumns, e := struct_columns(field.Type, index, true, seenfields)
+				if e != nil {
+					return nil, e
+				}
+				columns = append(columns, flattenedcolumns...)
+				continue
+			}
+		}
+
+		name := mormtag.fieldname
+		if flattened {
+			name = fmt.Sprintf("%s_%s", strings.ToLower(t.Name()), name)
+			if _, found := seenfields[name]; found {
+				name = fmt.Sprintf("%s_%s", strings.ToLower(t.Name()), name)
+			}
+		}
+		seenfields[name] = struct{}{}
+
+		columns = append(columns, column{name: safe_keyword(name), index: index, field: field, tag: mormtag})
+	}
+
+	return columns, nil
+}
+
+// project_columns maps the columns of the projection to the columns of source.
+//
+// A projection column maps by column name or, when the names differ, by Go field name. The returned columns keep
+// the projection index with the source column name
+func project_columns(projection, source reflect.Type) ([]column, error) {
+	sourcecolumns, e := model_columns(source)
+	if e != nil {
+		return nil, e
+	}
+
+	byname := make(map[string]column, len(sourcecolumns))
+	byfield := make(map[string][]column, len(sourcecolumns))
+	for _, c := range sourcecolumns {
+		byname[c.name] = c
+		byfield[c.field.Name] = append(byfield[c.field.Name], c)
+	}
+
+	columns, e := model_columns(projection)
+	if e != nil {
+		return nil, e
+	}
+
+	for i, c := range columns {
+		if sc, found := byname[c.name]; found {
+			columns[i].tag = sc.tag
+			continue
+		}
+
+		candidates := byfield[c.field.Name]
+		if len(candidates) != 1 {
+			return nil, fmt.Errorf("%w: %s.%s in %s", ErrUnmappedField, projection.Name(), c.field.Name, source.Name())
+		}
+
+		columns[i].name = candidates[0].name
+		columns[i].tag = candidates[0].tag
+	}
+
+	return columns, nil
+}
+
+// column_names returns the name of every column
+func column_names(columns []column) []string {
+	names := make([]string, 0, len(columns))
+	for _, c := range columns {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+// stored_value returns the value of a field the way [tostring] stores it in its column so it can be compared
+// or bound as a parameter. Nil pointers are nil
+func stored_value(v reflect.Value, c column) any {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if !istimetype(v.Type()) {
+		return v.Interface()
+	}
+
+	t := v.Interface().(time.Time)
+	switch strings.ToLower(c.tag.fieldtype) {
+	case "integer":
+		return t.UnixMilli()
+	case "real":
+		return unixEpochJD + float64(t.UTC().UnixNano())/86400e9
+	case "timestamp":
+		return t.Format(time.TimeOnly)
+	default:
+		return t.Format(time.DateTime)
+	}
+}
+
+// field_scanner is a [sql.Scanner] that writes a database value into a struct field
+type field_scanner struct {
+	dst reflect.Value
+	tag MormTag
+}
+
+func (fs *field_scanner) Scan(src any) error {
+	return assign(fs.dst, src, fs.tag)
+}
+
+// scan_row scans the current row into v, columns must be in the same order as the selected columns
+func scan_row(rows *sql.Rows, v reflect.Value, columns []column) error {
+	dest := make([]any, len(columns))
+	for i, c := range columns {
+		dest[i] = &field_scanner{dst: v.FieldByIndex(c.index), tag: c.tag}
+	}
+	return rows.Scan(dest...)
+}
+
+// assign sets src, a value returned by the driver, into dst converting it to the field type
+func assign(dst reflect.Value, src any, tag MormTag) error {
+	if dst.CanAddr() {
+		scanner, ok := dst.Addr().Interface().(sql.Scanner)
+		if ok {
+			return scanner.Scan(src)
+		}
+	}
+
+	if src == nil {
+		dst.SetZero()
+		return nil
+	}
+
+	if dst.Kind() == reflect.Pointer {
+		nv := reflect.New(dst.Type().Elem())
+		e := assign(nv.Elem(), src, tag)
+		if e != nil {
+			return e
+		}
+		dst.Set(nv)
+		return nil
+	}
+
+	if istimetype(dst.Type()) {
+		t, e := totime(src, tag)
+		if e != nil {
+			return e
+		}
+		dst.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.String:
+		switch s := src.(type) {
+		case string:
+			dst.SetString(s)
+		case []byte:
+			dst.SetString(string(s))
+		case time.Time:
+			dst.SetString(s.Format(time.DateTime))
+		default:
+			dst.SetString(fmt.Sprint(s))
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		switch s := src.(type) {
+		case int64:
+			n = s
+		case float64:
+			n = int64(s)
+		case bool:
+			if s {
+				n = 1
+			}
+		case []byte, string:
+			parsed, e := strconv.ParseInt(strings.TrimSpace(fmt.Sprintf("%s", s)), 10, 64)
+			if e != nil {
+				return err_wrap(e, fmt.Sprintf("unable to convert %q to %s", s, dst.Type()))
+			}
+			n = parsed
+		default:
+			return fmt.Errorf("unable to convert %T to %s", src, dst.Type())
+		}
+		if dst.OverflowInt(n) {
+			return fmt.Errorf("value %d overflows %s", n, dst.Type())
+		}
+		dst.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var n uint64
+		switch s := src.(type) {
+		case int64:
+			if s < 0 {
+				return fmt.Errorf("negative value %d can not be set into %s", s, dst.Type())
+			}
+			n = uint64(s)
+		case float64:
+			n = uint64(s)
+		case bool:
+			if s {
+				n = 1
+			}
+		case []byte, string:
+			parsed, e := strconv.ParseUint(strings.TrimSpace(fmt.Sprintf("%s", s)), 10, 64)
+			if e != nil {
+				return err_wrap(e, fmt.Sprintf("unable to convert %q to %s", s, dst.Type()))
+			}
+			n = parsed
+		default:
+			return fmt.Errorf("unable to convert %T to %s", src, dst.Type())
+		}
+		if dst.OverflowUint(n) {
+			return fmt.Errorf("value %d overflows %s", n, dst.Type())
+		}
+		dst.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		var n float64
+		switch s := src.(type) {
+		case float64:
+			n = s
+		case int64:
+			n = float64(s)
+		case []byte, string:
+			parsed, e := strconv.ParseFloat(strings.TrimSpace(fmt.Sprintf("%s", s)), 64)
+			if e != nil {
+				return err_wrap(e, fmt.Sprintf("unable to convert %q to %s", s, dst.Type()))
+			}
+			n = parsed
+		default:
+			return fmt.Errorf("unable to convert %T to %s", src, dst.Type())
+		}
+		dst.SetFloat(n)
+	case reflect.Bool:
+		switch s := src.(type) {
+		case bool:
+			dst.SetBool(s)
+		case int64:
+			dst.SetBool(s != 0)
+		case float64:
+			dst.SetBool(s != 0)
+		case []byte, string:
+			parsed, e := strconv.ParseBool(strings.TrimSpace(fmt.Sprintf("%s", s)))
+			if e != nil {
+				return err_wrap(e, fmt.Sprintf("unable to convert %q to %s", s, dst.Type()))
+			}
+			dst.SetBool(parsed)
+		default:
+			return fmt.Errorf("unable to convert %T to %s", src, dst.Type())
+		}
+	case reflect.Slice:
+		if dst.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("unable to scan into %s", dst.Type())
+		}
+		switch s := src.(type) {
+		case []byte:
+			dst.SetBytes(append([]byte(nil), s...))
+		case string:
+			dst.SetBytes([]byte(s))
+		default:
+			return fmt.Errorf("unable to convert %T to %s", src, dst.Type())
+		}
+	default:
+		return fmt.Errorf("unable to scan into %s", dst.Type())
+	}
+
+	return nil
+}
+
+// totime turns a database value into a [time.Time], it is the reverse of how [tostring] stores time
+// based on the column type in the tag
+func totime(src any, tag MormTag) (time.Time, error) {
+	switch s := src.(type) {
+	case time.Time:
+		return s, nil
+	case int64:
+		return time.UnixMilli(s), nil
+	case float64:
+		nano := (s - unixEpochJD) * 86400e9
+		return time.Unix(0, int64(nano)).UTC(), nil
+	case []byte:
+		return parsetime(string(s), tag)
+	case string:
+		return parsetime(s, tag)
+	default:
+		return time.Time{}, fmt.Errorf("unable to convert %T to time.Time", src)
+	}
+}
+
+// parsetime parses a textual time trying the layouts morm and the engines write
+func parsetime(s string, tag MormTag) (time.Time, error) {
+	layouts := []string{time.DateTime, time.RFC3339Nano, "2006-01-02 15:04:05.999999999-07:00", time.DateOnly}
+	if strings.ToLower(tag.fieldtype) == "timestamp" {
+		layouts = append([]string{time.TimeOnly}, layouts...)
+	}
+
+	for _, layout := range layouts {
+		t, e := time.Parse(layout, s)
+		if e == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unable to parse %q as time", s)
+}
@@ -0,0 +1,292 @@
+package morm
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// schema_table is a table of a model set and the tables it depends on
+type schema_table struct {
+	model     reflect.Type
+	tablename string
+
+	// link column to the parent record for adjecent tables
+	link *adjecent_link
+
+	// parent table of an adjecent table
+	parent string
+
+	// tables referenced by foreign keys of the columns
+	references []string
+}
+
+var references_regex = regexp.MustCompile(`(?i)\breferences\s+([\w\[\]."]+)`)
+
+// schema_tables returns the table of the model followed by its adjecent tables, nested ones included
+func schema_tables(model any, tablename string, parent string, link *adjecent_link) ([]schema_table, error) {
+	t, e := pulltype(model)
+	if e != nil {
+		return nil, e
+	}
+
+	if tablename == "" {
+		tablename = default_tablename(t)
+	}
+
+	childlink, e := new_adjecent_link(t, reflect.Value{})
+	if e != nil {
+		return nil, e
+	}
+
+	columns, e := model_columns(t)
+	if e != nil {
+		return nil, e
+	}
+
+	table := schema_table{model: t, tablename: tablename, link: link, parent: parent}
+	for _, c := range columns {
+		for _, match := range references_regex.FindAllStringSubmatch(c.tag.tag, -1) {
+			name := match[1]
+			if dot := strings.LastIndex(name, "."); dot != -1 {
+				name = name[dot+1:]
+			}
+
+			// note: the referenced columns may follow the name without a space
+			if open := strings.Index(name, "("); open != -1 {
+				name = name[:open]
+			}
+			table.references = append(table.references, strings.ToLower(strings.Trim(name, `[]"`)))
+		}
+	}
+
+	tables := []schema_table{table}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if tag, _ := gettag(field); !tag.IsEmpty() || field.Type.Kind() != reflect.Struct {
+			continue
+		}
+
+		children, e := schema_tables(field.Type, "", tablename, childlink)
+		if e != nil {
+			return nil, e
+		}
+		tables = append(tables, children...)
+	}
+
+	return tables, nil
+}
+
+// order_tables sorts the tables of models so every table comes after its parent and the tables it references.
+//
+// The order of models is kept when there is no dependency between tables. Dependencies on tables that are not part
+// of the set are ignored and a dependency cycle is an error
+func order_tables(models []any) ([]schema_table, error) {
+	var tables []schema_table
+	known := make(map[string]bool)
+	for _, model := range models {
+		modeltables, e := schema_tables(model, "", "", nil)
+		if e != nil {
+			return nil, e
+		}
+
+		for _, table := range modeltables {
+			if known[table.tablename] {
+				continue
+			}
+			known[table.tablename] = true
+			tables = append(tables, table)
+		}
+	}
+
+	ordered := make([]schema_table, 0, len(tables))
+	placed := make(map[string]bool, len(tables))
+	for len(ordered) < len(tables) {
+		progress := false
+		for _, table := range tables {
+			if placed[table.tablename] {
+				continue
+			}
+
+			ready := table.parent == "" || placed[table.parent] || !known[table.parent]
+			for _, reference := range table.references {
+				if reference != table.tablename && known[reference] && !placed[reference] {
+					ready = false
+				}
+			}
+
+			if !ready {
+				continue
+			}
+
+			ordered = append(ordered, table)
+			placed[table.tablename] = true
+			progress = true
+		}
+
+		if !progress {
+			var pending []string
+			for _, table := range tables {
+				if !placed[table.tablename] {
+					pending = append(pending, table.tablename)
+				}
+			}
+			return nil, fmt.Errorf("dependency cycle between tables %s", strings.Join(pending, ", "))
+		}
+	}
+
+	return ordered, nil
+}
+
+// CreateTables creates the tables of models and their adjecent tables if they don't exists.
+//
+// Tables are created after their parent and the tables their foreign keys reference, in a single transaction where
+// the engine supports transactional DDL. On SQLServer the database of the client is created first, outside of the
+// transaction. Result.Tables has every table processed
+func (m *MORM) CreateTables(models ...any) Result {
+	if m == nil {
+		return error_result(ErrDefaultClientIsNil)
+	}
+
+	tables, e := order_tables(models)
+	if e != nil {
+		return error_result(e)
+	}
+
+	queries := make([]string, len(tables))
+	for i, table := range tables {
+		query, e := m.table_query(table.model, table.tablename, table.link)
+		if e != nil {
+			return error_result(err_wrap(e, fmt.Sprintf("unable to compose table %s", table.tablename)))
+		}
+		queries[i] = query
+	}
+
+	e = create_database(m)
+	if e != nil {
+		return error_result(e)
+	}
+
+	return m.schema_exec(tables, queries, false)
+}
+
+// DropTables drops the tables of models and their adjecent tables if they exists.
+//
+// Tables are dropped before their parent and the tables their foreign keys reference, in a single transaction where
+// the engine supports transactional DDL. Result.Tables has every table processed
+func (m *MORM) DropTables(models ...any) Result {
+	if m == nil {
+		return error_result(ErrDefaultClientIsNil)
+	}
+
+	tables, e := order_tables(models)
+	if e != nil {
+		return error_result(e)
+	}
+	slices.Reverse(tables)
+
+	queries := make([]string, len(tables))
+	for i, table := range tables {
+		queries[i] = fmt.Sprintf("drop table if exists %s;", table.tablename)
+	}
+
+	return m.schema_exec(tables, queries, false)
+}
+
+// TruncateTables deletes every record of the tables of models and their adjecent tables.
+//
+// Tables are emptied before their parent and the tables their foreign keys reference, in a single transaction
+// where the engine supports it. SQLITE has no truncate so records are deleted, as they are on referenced tables.
+// Result.Tables has the records deleted by table, truncated tables don't report their records
+func (m *MORM) TruncateTables(models ...any) Result {
+	if m == nil {
+		return error_result(ErrDefaultClientIsNil)
+	}
+
+	tables, e := order_tables(models)
+	if e != nil {
+		return error_result(e)
+	}
+
+	referenced := make(map[string]bool)
+	for _, table := range tables {
+		for _, reference := range table.references {
+			referenced[reference] = true
+		}
+	}
+
+	slices.Reverse(tables)
+
+	queries := make([]string, len(tables))
+	for i, table := range tables {
+		if m.engine == SQLITE || referenced[table.tablename] {
+			queries[i] = fmt.Sprintf("delete from %s;", table.tablename)
+			continue
+		}
+		queries[i] = fmt.Sprintf("truncate table %s;", table.tablename)
+	}
+
+	return m.schema_exec(tables, queries, true)
+}
+
+// schema_exec executes the query of every table in order, MySQL commits DDL implicitly so it runs without
+// transaction and the tables processed before an error are reported
+func (m *MORM) schema_exec(tables []schema_table, queries []string, count bool) Result {
+	if m.engine == MySQL {
+		if !m.connected {
+			e := m.connect()
+			if e != nil {
+				return error_result(e)
+			}
+		}
+
+		rslt := Result{Tables: make(map[string]int64, len(tables))}
+		for i, table := range tables {
+			_queryHistory = append(_queryHistory, queries[i])
+			sqlr, e := m.db.Exec(queries[i])
+			if e != nil {
+				rslt.Error = err_wrap(e, fmt.Sprintf("unable to process table %s", table.tablename))
+				return rslt
+			}
+			schema_affected(&rslt, table.tablename, sqlr, count)
+		}
+		return rslt
+	}
+
+	tx, e := begin_tx(m)
+	if e != nil {
+		return error_result(e)
+	}
+
+	rslt := Result{Tables: make(map[string]int64, len(tables))}
+	for i, table := range tables {
+		_queryHistory = append(_queryHistory, queries[i])
+		sqlr, e := tx.Exec(queries[i])
+		if e != nil {
+			tx.Rollback()
+			return error_result(err_wrap(e, fmt.Sprintf("unable to process table %s", table.tablename)))
+		}
+		schema_affected(&rslt, table.tablename, sqlr, count)
+	}
+
+	if e := tx.Commit(); e != nil {
+		return error_result(e)
+	}
+
+	return rslt
+}
+
+// schema_affected records the rows affected of the table when count is set, otherwise the table counts 0 since DDL
+// doesn't affect rows and some drivers report the count of the previous statement
+func schema_affected(rslt *Result, tablename string, sqlr sql.Result, count bool) {
+	var n int64
+	if count {
+		n, _ = sqlr.RowsAffected()
+		n = max(n, 0)
+	}
+	rslt.Tables[tablename] += n
+	rslt.RowsAffected += n
+}
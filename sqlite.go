@@ -13,39 +13,48 @@ func sqlite_createtable_query(table, columns string) string {
 //TODO: 2026-02-05 test this function
 
 // sqlite_notag_column composes a field from a notag struct field
-func sqlite_notag_column(field reflect.StructField, fieldname, tablename string) Field {
+func sqlite_notag_column(field reflect.StructField, fieldname, tablename string) (Field, error) {
 	kind := field.Type.Kind()
 	switch kind {
 	case reflect.Slice, reflect.Array, reflect.Map:
 		k := field.Type.Elem().Kind()
-		sqltype := sqlite_kind_to_sqltype(k)
+		sqltype, e := sqlite_kind_to_sqltype(k)
+		if e != nil {
+			return Field{}, e
+		}
 		sqltype = fmt.Sprintf("value %s", sqltype)
 		if kind == reflect.Bool {
 			sqltype = fmt.Sprintf("%s check (value IN(0,1))", sqltype)
 		}
 		query := fmt.Sprintf("create table if not exists %s_%s (%s)", tablename, fieldname, sqltype)
-		return Field{FieldType: ADJECENT_TABLE, query: query}
+		return Field{FieldType: ADJECENT_TABLE, query: query}, nil
 	default:
-		sqltype := sqlite_kind_to_sqltype(kind)
+		sqltype, e := sqlite_kind_to_sqltype(kind)
+		if e != nil {
+			return Field{}, e
+		}
 		sqltype = fmt.Sprintf("%s %s", fieldname, sqltype)
 		if kind == reflect.Bool {
 			sqltype = fmt.Sprintf("%s check (%s IN(0,1))", sqltype, fieldname)
 		}
-		return NewField(sqltype)
+		return NewField(sqltype), nil
 	}
 }
 
 // sqllite_kind_to_sqltype takes a native k Kind and fieldname.
 //
 // Returns the sql type it should map to along the fieldname.
-func sqlite_kind_to_sqltype(k reflect.Kind) string {
+func sqlite_kind_to_sqltype(k reflect.Kind) (string, error) {
 	switch k {
-	case reflect.Int, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Bool:
-		return "integer"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Bool,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer", nil
+	case reflect.Float32, reflect.Float64:
+		return "real", nil
 	case reflect.String:
-		return "text"
+		return "text", nil
 	//TODO:(richard) handle DATETIME
 	default:
-		panic(fmt.Sprintf("this type is not supported %s", k))
+		return "", fmt.Errorf("%w: %s has no sqlite type", ErrUnsupportedType, k)
 	}
 }
@@ -5,22 +5,32 @@ import (
 	"reflect"
 )
 
-func mssql_createtable_query(table, columns, dbname string, m *MORM) (string, error) {
+// mssql_create_database creates the database of the client if it does not exists, it can't run in a transaction
+func mssql_create_database(m *MORM) error {
+	if m.databasename == "" {
+		return nil
+	}
+
+	query := `
+	IF DB_ID(N'%s') IS NULL
+	BEGIN
+		CREATE DATABASE %s;
+	END;
+	`
+	query = fmt.Sprintf(query, m.databasename, m.databasename)
+	_queryHistory = append(_queryHistory, query)
+
+	_, e := m.Exec(query)
+	if e != nil {
+		return err_wrap(e, fmt.Sprintf("unable to create database %s", m.databasename))
+	}
+	return nil
+}
+
+func mssql_createtable_query(table, columns, dbname string) string {
 
 	var query string
 	if dbname != "" {
-		create_db_query := `
-		IF DB_ID(N'%s') IS NULL
-		BEGIN
-			CREATE DATABASE %s;
-		END;
-		`
-		create_db_query = fmt.Sprintf(create_db_query, dbname, dbname)
-		_, e := m.Exec(create_db_query)
-		if e != nil {
-			return "", e
-		}
-
 		query = fmt.Sprintf("USE %s;\n\n", dbname)
 	}
 
@@ -32,29 +42,33 @@ END
 	`
 
 	query = fmt.Sprintf(query, table, table, columns)
-	return query, nil
+	return query
 }
 
 // mssql_notag_column composes a no tag (a field without the morm tag)
-func mssql_notag_column(field reflect.StructField, fieldname, tablename string) Field {
+func mssql_notag_column(field reflect.StructField, fieldname, tablename string) (Field, error) {
 	switch field.Type.Kind() {
 	case reflect.Int8:
-		return NewField(fmt.Sprintf("%s TINYINT", fieldname))
+		return NewField(fmt.Sprintf("%s TINYINT", fieldname)), nil
 	case reflect.Int16:
-		return NewField(fmt.Sprintf("%s SMALLINT", fieldname))
+		return NewField(fmt.Sprintf("%s SMALLINT", fieldname)), nil
 	case reflect.Int32:
-		return NewField(fmt.Sprintf("%s INT", fieldname))
-	case reflect.Int64:
-		return NewField(fmt.Sprintf("%s BIGINT", fieldname))
+		return NewField(fmt.Sprintf("%s INT", fieldname)), nil
+	case reflect.Int64, reflect.Int:
+		return NewField(fmt.Sprintf("%s BIGINT", fieldname)), nil
+	case reflect.Float32:
+		return NewField(fmt.Sprintf("%s REAL", fieldname)), nil
+	case reflect.Float64:
+		return NewField(fmt.Sprintf("%s FLOAT", fieldname)), nil
 	case reflect.String:
-		return NewField(fmt.Sprintf("%s varchar(max)", fieldname))
+		return NewField(fmt.Sprintf("%s varchar(max)", fieldname)), nil
 	case reflect.Bool:
-		return NewField(fmt.Sprintf("%s BIT", fieldname))
+		return NewField(fmt.Sprintf("%s BIT", fieldname)), nil
 	case reflect.Array, reflect.Slice, reflect.Map:
 		//TODO: handle arrays and slices by creatin an adjecent table by creating am adjecent table
-		return Field{}
+		return Field{}, nil
 	default:
-		panic(fmt.Sprintf("this type is not supported %s", field.Type.Kind()))
+		return Field{}, fmt.Errorf("%w: %s has no sql server type", ErrUnsupportedType, field.Type.Kind())
 	}
 
 }
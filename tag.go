@@ -4,8 +4,6 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
-
-	. "github.com/chapgx/assert/v2"
 )
 
 // MormTag is a representation of the morm notation tag
@@ -26,38 +24,84 @@ func (mt MormTag) IsDirective() bool {
 	return mt.tag[0] == ':'
 }
 
+// IsPrimary checks if the tag declares the column as primary key
+func (mt MormTag) IsPrimary() bool {
+	if len(mt.split) < 2 {
+		return false
+	}
+
+	for _, s := range mt.split[1:] {
+		if strings.EqualFold(s, "primary") {
+			return true
+		}
+	}
+	return false
+}
+
+// IsUnique checks if the tag declares the column as unique, primary keys are unique as well
+func (mt MormTag) IsUnique() bool {
+	if mt.IsPrimary() {
+		return true
+	}
+
+	if len(mt.split) < 2 {
+		return false
+	}
+
+	for _, s := range mt.split[1:] {
+		if strings.EqualFold(s, "unique") {
+			return true
+		}
+	}
+	return false
+}
+
 func (mt *MormTag) SetFieldName(fn string) {
 	mt.fieldname = fn
 	mt.split[0] = fn
 	mt.tag = strings.Join(mt.split, " ")
 }
 
-// gettag creates a [MormTag] from the struct field
-func gettag(field reflect.StructField) MormTag {
+// gettag creates a [MormTag] from the struct field, a tag that is not a directive must have a column name and type.
+// The only directives are [IgnoreDirective] and [FlattenDirective], which applies to struct fields only
+func gettag(field reflect.StructField) (MormTag, error) {
 	mormtag := field.Tag.Get("morm")
 	mt := MormTag{tag: mormtag}
 
 	if mt.IsEmpty() {
 		mt.fieldname = strings.ToLower(field.Name)
-		return mt
+		return mt, nil
 	}
 
-	if !mt.IsDirective() {
-		mt.split = strings.Split(mt.tag, " ")
+	if mt.IsDirective() {
+		switch mt.tag {
+		case IgnoreDirective:
+		case FlattenDirective:
+			if field.Type.Kind() != reflect.Struct {
+				return mt, fmt.Errorf("%w: %s applies to struct fields not %s", ErrInvalidTag, mt.tag, field.Type)
+			}
+		default:
+			return mt, fmt.Errorf("%w: unknown directive %q", ErrInvalidTag, mt.tag)
+		}
+	} else {
+		mt.split = strings.Fields(mt.tag)
+		if len(mt.split) < 2 {
+			return mt, fmt.Errorf("%w: %q must have a column name and type", ErrInvalidTag, mt.tag)
+		}
 		mt.fieldname = mt.split[0]
 		mt.fieldtype = mt.split[1]
 	}
 
-	return mt
+	return mt, nil
 }
 
-func emptytagprocess(field reflect.StructField, v reflect.Value, t reflect.Type, index int, seenfields map[string]bool) (name, value string, query []string) {
+// emptytagprocess returns the column name and value of an untagged field, adjecent is set instead if the field is a
+// struct which records are inserted in their own table after the parent record
+func emptytagprocess(field reflect.StructField, v reflect.Value, t reflect.Type, index int, seenfields map[string]bool) (name string, value any, adjecent bool, e error) {
 
 	// struct control structure
 	if field.Type.Kind() == reflect.Struct {
-		iface := v.Field(index).Interface()
-		query = insert_adjecent(iface, nil)
-		return "", "", query
+		return "", nil, true, nil
 	}
 
 	if seenfields == nil {
@@ -75,8 +119,10 @@ func emptytagprocess(field reflect.StructField, v reflect.Value, t reflect.Type,
 	name = safe_keyword(name)
 
 	fieldvalue := v.Field(index)
-	value, e := tostring(fieldvalue, field.Type, MormTag{})
-	Assert(e == nil, e)
+	value, e = field_arg(fieldvalue, field.Type, MormTag{})
+	if e != nil {
+		return "", nil, false, field_error(t, field, e)
+	}
 
-	return name, value, nil
+	return name, value, false, nil
 }
@@ -0,0 +1,78 @@
+package test
+
+import (
+	"embed"
+	"path/filepath"
+	"testing"
+
+	. "github.com/chapgx/assert/v2"
+	"github.com/chapgx/morm"
+)
+
+func TestNamedParams(t *testing.T) {
+	orm, e := morm.New(morm.SQLITE, filepath.Join(t.TempDir(), "named.db"))
+	AssertT(t, e == nil, e)
+	defer orm.Close()
+
+	e = orm.CreateTable(plane{}, "")
+	AssertT(t, e == nil, e)
+
+	p := plane{Number: "BOING_00", Weight: 15, Manufacture: "Boing"}
+	_, e = orm.Exec("insert into planes(number, weight, manufacture) values (:number, :weight, :manufacture)", p)
+	AssertT(t, e == nil, e)
+
+	_, e = orm.Exec(
+		"insert into planes(number, weight, manufacture) values (:number, :weight, ':not_a_param') -- :comment",
+		map[string]any{"number": "BOING_01", "weight": 20},
+	)
+	AssertT(t, e == nil, e)
+
+	var planes []plane
+	e = orm.QueryInto(&planes, "select * from planes where weight >= :min and weight < :min + 10 /* :max */", map[string]any{"min": 15})
+	AssertT(t, e == nil && len(planes) == 2, planes)
+	AssertT(t, planes[1].Manufacture == ":not_a_param", planes[1])
+
+	row, e := orm.QueryRow("select count(*) from planes where number = :Number", &p)
+	AssertT(t, e == nil, e)
+
+	var count int
+	e = row.Scan(&count)
+	AssertT(t, e == nil && count == 1, count)
+
+	_, e = orm.Query("select * from planes where number = :missing", map[string]any{})
+	AssertT(t, e != nil, "expected an error for a named parameter without value")
+}
+
+//go:embed queries/*.sql
+var queries embed.FS
+
+func TestNamedQueries(t *testing.T) {
+	orm, e := morm.New(morm.SQLITE, filepath.Join(t.TempDir(), "queries.db"))
+	AssertT(t, e == nil, e)
+	defer orm.Close()
+
+	e = orm.LoadQueries(queries, "queries/*.sql")
+	AssertT(t, e == nil, e)
+
+	e = orm.CreateTable(plane{}, "")
+	AssertT(t, e == nil, e)
+
+	for _, p := range []plane{{Number: "BOING_00", Weight: 15}, {Number: "BOING_01", Weight: 25}, {Number: "BOING_02", Weight: 35}} {
+		e = orm.Insert(&p)
+		AssertT(t, e == nil, e)
+	}
+
+	_, e = orm.ExecNamed("RenamePlane", map[string]any{"from": "BOING_02", "to": "AIRBUS_00"})
+	AssertT(t, e == nil, e)
+
+	var heavy []planeWeight
+	e = orm.QueryNamedInto(&heavy, "HeavyPlanes", map[string]any{"min": 20})
+	AssertT(t, e == nil, e)
+	AssertT(t, len(heavy) == 2 && heavy[0].Code == "AIRBUS_00", heavy)
+
+	_, e = orm.QueryNamed("Missing")
+	AssertT(t, e != nil, "expected an error for a query that is not registered")
+
+	e = orm.LoadQueries(queries, "queries/*.sql")
+	AssertT(t, e != nil, "expected an error registering the same queries twice")
+}
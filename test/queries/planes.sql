@@ -0,0 +1,17 @@
+-- reports over the planes table
+
+-- name: HeavyPlanes
+select number, weight
+from planes
+where weight >= :min
+order by number;
+
+-- name: HeavyPlanes
+-- engine: SQLServer
+select number, weight
+from planes
+where weight >= :min
+order by number desc;
+
+-- name: RenamePlane
+update planes set number = :to where number = :from;
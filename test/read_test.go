@@ -0,0 +1,441 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	. "github.com/chapgx/assert/v2"
+	"github.com/chapgx/morm"
+)
+
+type plane struct {
+	Number      string  `morm:"number text"`
+	Weight      int64   `morm:"weight integer"`
+	Active      bool    `morm:"active integer"`
+	Nickname    *string `morm:"nickname text null"`
+	Manufacture string
+}
+
+func TestRead(t *testing.T) {
+	orm, e := morm.New(morm.SQLITE, filepath.Join(t.TempDir(), "read.db"))
+	AssertT(t, e == nil, e)
+	defer orm.Close()
+
+	e = orm.CreateTable(plane{}, "")
+	AssertT(t, e == nil, e)
+
+	nickname := "Big Bird"
+	for _, p := range []plane{
+		{Number: "BOING_00", Weight: 15, Active: true, Nickname: &nickname, Manufacture: "Boing"},
+		{Number: "BOING_01", Weight: 20, Manufacture: "Boing"},
+		{Number: "AIRBUS_00", Weight: 30, Active: true, Manufacture: "Airbus"},
+	} {
+		e = orm.Insert(&p)
+		AssertT(t, e == nil, e)
+	}
+
+	t.Run("single", func(t *testing.T) {
+		filter := morm.NewFilter()
+		filter.And("number", morm.EQUAL, "BOING_00")
+
+		var p plane
+		e := orm.Read(&p, &filter, "")
+		AssertT(t, e == nil, e)
+		AssertT(t, p.Number == "BOING_00" && p.Weight == 15 && p.Active, p)
+		AssertT(t, p.Nickname != nil && *p.Nickname == nickname, p.Nickname)
+		AssertT(t, p.Manufacture == "Boing", p.Manufacture)
+	})
+
+	t.Run("slice", func(t *testing.T) {
+		filter := morm.NewFilter()
+		filter.And("manufacture", morm.EQUAL, "Boing")
+
+		var planes []plane
+		e := orm.Read(&planes, &filter, "")
+		AssertT(t, e == nil, e)
+		AssertT(t, len(planes) == 2, planes)
+		AssertT(t, planes[1].Nickname == nil, planes[1].Nickname)
+
+		var all []*plane
+		e = orm.Read(&all, nil, "planes")
+		AssertT(t, e == nil, e)
+		AssertT(t, len(all) == 3, all)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		filter := morm.NewFilter()
+		filter.And("number", morm.EQUAL, "CESSNA_00")
+
+		var p plane
+		e := orm.Read(&p, &filter, "")
+		AssertT(t, errors.Is(e, morm.ErrNotFound), e)
+	})
+}
+
+type pilot struct {
+	ID      string  `morm:"id text"`
+	Name    string  `morm:"name text"`
+	Contact contact `morm:":flatten"`
+}
+
+type contact struct {
+	Primary bool
+	Number  string    `morm:"number text"`
+	Ext     extension `morm:":flatten"`
+}
+
+type extension struct {
+	Code   string `morm:"code text"`
+	Active bool
+}
+
+func TestReadFlatten(t *testing.T) {
+	orm, e := morm.New(morm.SQLITE, filepath.Join(t.TempDir(), "flatten.db"))
+	AssertT(t, e == nil, e)
+	defer orm.Close()
+
+	e = orm.CreateTable(pilot{}, "")
+	AssertT(t, e == nil, e)
+
+	p := pilot{
+		ID:      "00",
+		Name:    "Amelia",
+		Contact: contact{Primary: true, Number: "555-0100", Ext: extension{Code: "42", Active: true}},
+	}
+	e = orm.Insert(&p)
+	AssertT(t, e == nil, e)
+
+	var got pilot
+	e = orm.Read(&got, nil, "")
+	AssertT(t, e == nil, e)
+	AssertT(t, got == p, got)
+}
+
+func TestReadIter(t *testing.T) {
+	orm, e := morm.New(morm.SQLITE, filepath.Join(t.TempDir(), "iter.db"))
+	AssertT(t, e == nil, e)
+	defer orm.Close()
+
+	e = orm.CreateTable(plane{}, "")
+	AssertT(t, e == nil, e)
+
+	for i := range 10 {
+		p := plane{Number: fmt.Sprintf("BOING_%02d", i), Weight: int64(i)}
+		e = orm.Insert(&p)
+		AssertT(t, e == nil, e)
+	}
+
+	filter := morm.NewFilter()
+	filter.And("weight", morm.GREATER_OR_EQ, 5)
+
+	var weights []int64
+	for p, e := range morm.ReadIter[plane](orm, &filter, "") {
+		AssertT(t, e == nil, e)
+		weights = append(weights, p.Weight)
+	}
+	AssertT(t, len(weights) == 5 && weights[0] == 5, weights)
+
+	count := 0
+	for _, e := range morm.ReadIter[plane](orm, nil, "") {
+		AssertT(t, e == nil, e)
+		count++
+		if count == 3 {
+			break
+		}
+	}
+	AssertT(t, count == 3, count)
+
+	for _, e := range morm.ReadIter[string](orm, nil, "") {
+		AssertT(t, e != nil, "expected an error iterating a non struct type")
+	}
+}
+
+type planeWeight struct {
+	Code   string `morm:"number text"`
+	Weight int64
+}
+
+type planeColor struct {
+	Number string
+	Color  string
+}
+
+func TestReadProjection(t *testing.T) {
+	orm, e := morm.New(morm.SQLITE, filepath.Join(t.TempDir(), "projection.db"))
+	AssertT(t, e == nil, e)
+	defer orm.Close()
+
+	e = orm.CreateTable(plane{}, "")
+	AssertT(t, e == nil, e)
+
+	p := plane{Number: "BOING_00", Weight: 15, Manufacture: "Boing"}
+	e = orm.Insert(&p)
+	AssertT(t, e == nil, e)
+
+	var weights []planeWeight
+	e = orm.Read(&weights, nil, "", morm.FromModel(plane{}))
+	AssertT(t, e == nil, e)
+	AssertT(t, len(weights) == 1 && weights[0] == planeWeight{"BOING_00", 15}, weights)
+
+	var colors []planeColor
+	e = orm.Read(&colors, nil, "", morm.FromModel(plane{}))
+	AssertT(t, errors.Is(e, morm.ErrUnmappedField), e)
+}
+
+func TestReadMaps(t *testing.T) {
+	orm, e := morm.New(morm.SQLITE, filepath.Join(t.TempDir(), "maps.db"))
+	AssertT(t, e == nil, e)
+	defer orm.Close()
+
+	e = orm.CreateTable(plane{}, "")
+	AssertT(t, e == nil, e)
+
+	p := plane{Number: "BOING_00", Weight: 15, Active: true, Manufacture: "Boing"}
+	e = orm.Insert(&p)
+	AssertT(t, e == nil, e)
+
+	filter := morm.NewFilter()
+	filter.And("number", morm.EQUAL, "BOING_00")
+
+	records, e := orm.ReadMaps("planes", &filter)
+	AssertT(t, e == nil, e)
+	AssertT(t, len(records) == 1, records)
+	AssertT(t, records[0]["number"] == "BOING_00", records[0])
+	AssertT(t, records[0]["weight"] == int64(15), records[0])
+	AssertT(t, records[0]["nickname"] == nil, records[0])
+
+	records, e = orm.QueryMaps("select count(*) as total, x'cafe' as raw from planes where weight > ?", 10)
+	AssertT(t, e == nil, e)
+	AssertT(t, records[0]["total"] == int64(1), records[0])
+	raw, ok := records[0]["raw"].([]byte)
+	AssertT(t, ok && len(raw) == 2, records[0])
+}
+
+type pilotLicense struct {
+	ID     int64  `morm:"id integer primary key"`
+	Holder string `morm:"holder text"`
+}
+
+func TestFind(t *testing.T) {
+	orm, e := morm.New(morm.SQLITE, filepath.Join(t.TempDir(), "find.db"))
+	AssertT(t, e == nil, e)
+	defer orm.Close()
+
+	e = orm.CreateTable(pilotLicense{}, "")
+	AssertT(t, e == nil, e)
+
+	for _, l := range []pilotLicense{{3, "Amelia"}, {1, "Bessie"}, {2, "Amelia"}} {
+		e = orm.Insert(&l)
+		AssertT(t, e == nil, e)
+	}
+
+	var l pilotLicense
+	e = orm.FindByID(&l, 2, "")
+	AssertT(t, e == nil && l.ID == 2, e)
+
+	e = orm.FindByID(&l, 9, "")
+	AssertT(t, errors.Is(e, morm.ErrNotFound), e)
+
+	e = orm.FindByID(&plane{}, 1, "")
+	AssertT(t, errors.Is(e, morm.ErrNoPrimaryKey), e)
+
+	filter := morm.NewFilter()
+	filter.And("holder", morm.EQUAL, "Amelia")
+
+	e = orm.First(&l, &filter, "")
+	AssertT(t, e == nil && l.ID == 2, l)
+
+	exists, e := orm.Exists(pilotLicense{}, &filter, "")
+	AssertT(t, e == nil && exists, e)
+
+	count, e := orm.Count(pilotLicense{}, &filter, "")
+	AssertT(t, e == nil && count == 2, count)
+
+	count, e = orm.Count(pilotLicense{}, nil, "")
+	AssertT(t, e == nil && count == 3, count)
+
+	none := morm.NewFilter()
+	none.And("holder", morm.EQUAL, "Charles")
+	exists, e = orm.Exists(pilotLicense{}, &none, "")
+	AssertT(t, e == nil && !exists, e)
+}
+
+func TestReadOrderLimitOffset(t *testing.T) {
+	orm, e := morm.New(morm.SQLITE, filepath.Join(t.TempDir(), "order.db"))
+	AssertT(t, e == nil, e)
+	defer orm.Close()
+
+	e = orm.CreateTable(plane{}, "")
+	AssertT(t, e == nil, e)
+
+	nickname := "Big Bird"
+	for i := range 5 {
+		p := plane{Number: fmt.Sprintf("BOING_%02d", i), Weight: int64(i % 3)}
+		if i == 1 {
+			p.Nickname = &nickname
+		}
+		e = orm.Insert(&p)
+		AssertT(t, e == nil, e)
+	}
+
+	numbers := func(planes []plane) []string {
+		var n []string
+		for _, p := range planes {
+			n = append(n, p.Number)
+		}
+		return n
+	}
+
+	var planes []plane
+	e = orm.Read(&planes, nil, "", morm.OrderBy(morm.Desc("weight"), morm.Asc("number")), morm.Limit(3))
+	AssertT(t, e == nil, e)
+	AssertT(t, fmt.Sprint(numbers(planes)) == "[BOING_02 BOING_01 BOING_04]", numbers(planes))
+
+	e = orm.Read(&planes, nil, "", morm.OrderBy(morm.Desc("weight"), morm.Asc("number")), morm.Limit(2), morm.Offset(3))
+	AssertT(t, e == nil, e)
+	AssertT(t, fmt.Sprint(numbers(planes)) == "[BOING_00 BOING_03]", numbers(planes))
+
+	e = orm.Read(&planes, nil, "", morm.OrderBy(morm.Asc("number")), morm.Offset(4))
+	AssertT(t, e == nil, e)
+	AssertT(t, fmt.Sprint(numbers(planes)) == "[BOING_04]", numbers(planes))
+
+	var p plane
+	e = orm.Read(&p, nil, "", morm.OrderBy(morm.Asc("nickname").NullsLast(), morm.Desc("number")))
+	AssertT(t, e == nil && p.Number == "BOING_01", p)
+
+	e = orm.Read(&p, nil, "", morm.OrderBy(morm.Desc("nickname").NullsFirst(), morm.Desc("number")))
+	AssertT(t, e == nil && p.Number == "BOING_04", p)
+
+	e = orm.Read(&planes, nil, "", morm.OrderBy(morm.Asc("number; drop table planes")))
+	AssertT(t, errors.Is(e, morm.ErrUnmappedField), e)
+
+	e = orm.Read(&planes, nil, "", morm.OrderBy(morm.Order{Column: "number", Direction: "asc; drop table planes"}))
+	AssertT(t, e != nil, "expected an error ordering by an invalid direction")
+
+	e = orm.Read(&planes, nil, "", morm.OrderBy(morm.Order{Column: "number", Nulls: morm.NullsOrder(7)}))
+	AssertT(t, e != nil, "expected an error ordering by an invalid nulls order")
+
+	e = orm.Read(&planes, nil, "", morm.OrderBy(morm.Order{Column: "number", Direction: "DESC"}), morm.Limit(1))
+	AssertT(t, e == nil && fmt.Sprint(numbers(planes)) == "[BOING_04]", numbers(planes))
+}
+
+func TestReadPage(t *testing.T) {
+	orm, e := morm.New(morm.SQLITE, filepath.Join(t.TempDir(), "page.db"))
+	AssertT(t, e == nil, e)
+	defer orm.Close()
+
+	e = orm.CreateTable(pilotLicense{}, "")
+	AssertT(t, e == nil, e)
+
+	holders := []string{"Amelia", "Bessie", "Charles"}
+	for i := 1; i <= 8; i++ {
+		l := pilotLicense{ID: int64(i), Holder: holders[i%3]}
+		e = orm.Insert(&l)
+		AssertT(t, e == nil, e)
+	}
+
+	ids := func(licenses []pilotLicense) string {
+		var s []int64
+		for _, l := range licenses {
+			s = append(s, l.ID)
+		}
+		return fmt.Sprint(s)
+	}
+
+	req := morm.PageRequest{Keys: []morm.Order{morm.Asc("holder"), morm.Desc("id")}, Size: 3}
+
+	var licenses []pilotLicense
+	page, e := orm.ReadPage(&licenses, nil, "", req)
+	AssertT(t, e == nil, e)
+	AssertT(t, ids(licenses) == "[6 3 7]", ids(licenses))
+	AssertT(t, page.Next != "" && page.Prev == "", page)
+
+	req.Token = page.Next
+	page, e = orm.ReadPage(&licenses, nil, "", req)
+	AssertT(t, e == nil, e)
+	AssertT(t, ids(licenses) == "[4 1 8]", ids(licenses))
+	AssertT(t, page.Next != "" && page.Prev != "", page)
+	prev := page.Prev
+
+	req.Token = page.Next
+	page, e = orm.ReadPage(&licenses, nil, "", req)
+	AssertT(t, e == nil, e)
+	AssertT(t, ids(licenses) == "[5 2]", ids(licenses))
+	AssertT(t, page.Next == "" && page.Prev != "", page)
+
+	req.Token = prev
+	page, e = orm.ReadPage(&licenses, nil, "", req)
+	AssertT(t, e == nil, e)
+	AssertT(t, ids(licenses) == "[6 3 7]", ids(licenses))
+	AssertT(t, page.Next != "" && page.Prev == "", page)
+
+	filter := morm.NewFilter()
+	filter.And("holder", morm.NOT_EQUAL, "Bessie")
+	req.Token = ""
+	page, e = orm.ReadPage(&licenses, &filter, "", req)
+	AssertT(t, e == nil, e)
+	req.Token = page.Next
+	_, e = orm.ReadPage(&licenses, &filter, "", req)
+	AssertT(t, e == nil, e)
+	AssertT(t, ids(licenses) == "[5 2]", ids(licenses))
+
+	tampered := []byte(page.Next)
+	tampered[3] ^= 1
+	req.Token = string(tampered)
+	_, e = orm.ReadPage(&licenses, nil, "", req)
+	AssertT(t, errors.Is(e, morm.ErrInvalidPageToken), e)
+
+	// the cursor applies to every record matching an or filter
+	either := morm.NewFilter()
+	either.And("holder", morm.EQUAL, "Amelia").Or("id", morm.EQUAL, 8)
+	page, e = orm.ReadPage(&licenses, &either, "", morm.PageRequest{Keys: req.Keys, Size: 2})
+	AssertT(t, e == nil && ids(licenses) == "[6 3]", ids(licenses))
+	page, e = orm.ReadPage(&licenses, &either, "", morm.PageRequest{Keys: req.Keys, Size: 2, Token: page.Next})
+	AssertT(t, e == nil && ids(licenses) == "[8]", ids(licenses))
+	AssertT(t, page.Next == "", page)
+
+	req.Keys = []morm.Order{morm.Asc("holder")}
+	req.Token = ""
+	_, e = orm.ReadPage(&licenses, nil, "", req)
+	AssertT(t, e != nil, "expected an error for keys not ending in a unique column")
+}
+
+type planeReport struct {
+	Code     string `morm:"number text"`
+	Weight   int64
+	Nickname *string
+}
+
+func TestQueryInto(t *testing.T) {
+	orm, e := morm.New(morm.SQLITE, filepath.Join(t.TempDir(), "query.db"))
+	AssertT(t, e == nil, e)
+	defer orm.Close()
+
+	e = orm.CreateTable(plane{}, "")
+	AssertT(t, e == nil, e)
+
+	nickname := "Big Bird"
+	for _, p := range []plane{{Number: "BOING_00", Weight: 15, Nickname: &nickname}, {Number: "BOING_01", Weight: 20}} {
+		e = orm.Insert(&p)
+		AssertT(t, e == nil, e)
+	}
+
+	var reports []planeReport
+	e = orm.QueryInto(&reports, "select number, weight * 2 as weight, nickname, active from planes order by number")
+	AssertT(t, e == nil, e)
+	AssertT(t, len(reports) == 2 && reports[1].Weight == 40 && reports[1].Nickname == nil, reports)
+	AssertT(t, reports[0].Nickname != nil && *reports[0].Nickname == nickname, reports[0])
+
+	var heavy []*planeReport
+	e = orm.QueryInto(&heavy, "select number from planes where weight > ?", 18)
+	AssertT(t, e == nil && len(heavy) == 1 && heavy[0].Code == "BOING_01", heavy)
+
+	var single planeReport
+	e = orm.QueryInto(&single, "select number from planes where weight > ?", 100)
+	AssertT(t, errors.Is(e, morm.ErrNotFound), e)
+
+	e = orm.QueryIntoStrict(&reports, "select number, active from planes")
+	AssertT(t, errors.Is(e, morm.ErrUnmappedField), e)
+}
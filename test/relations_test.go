@@ -0,0 +1,69 @@
+package test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	. "github.com/chapgx/assert/v2"
+	"github.com/chapgx/morm"
+)
+
+type customer struct {
+	ID      string `morm:"id text primary key"`
+	Name    string `morm:"name text"`
+	Address address
+}
+
+type address struct {
+	Street string
+	City   string
+}
+
+type station struct {
+	ID      complex64 `morm:"id text primary key"`
+	Address address
+}
+
+func TestReadWithRelations(t *testing.T) {
+	orm, e := morm.New(morm.SQLITE, filepath.Join(t.TempDir(), "relations.db"))
+	AssertT(t, e == nil, e)
+	defer orm.Close()
+
+	e = orm.CreateTable(customer{}, "")
+	AssertT(t, e == nil, e)
+
+	customers := []customer{
+		{ID: "00", Name: "Ada", Address: address{Street: "1 Loop Rd", City: "London"}},
+		{ID: "01", Name: "Grace", Address: address{Street: "2 Cobol Ave", City: "Arlington"}},
+		{ID: "02", Name: "Linus"},
+	}
+	for _, c := range customers {
+		e = orm.Insert(&c)
+		AssertT(t, e == nil, e)
+	}
+
+	var got []customer
+	e = orm.Read(&got, nil, "", morm.WithRelations("Address"))
+	AssertT(t, e == nil, e)
+	AssertT(t, len(got) == 3, got)
+	for i := range customers {
+		AssertT(t, got[i] == customers[i], got[i])
+	}
+
+	filter := morm.NewFilter()
+	filter.And("id", morm.EQUAL, "01")
+
+	var one customer
+	e = orm.Read(&one, &filter, "", morm.WithRelations("Address"))
+	AssertT(t, e == nil, e)
+	AssertT(t, one == customers[1], one)
+
+	e = orm.Read(&one, &filter, "", morm.WithRelations("Name"))
+	AssertT(t, e != nil, "expected an error reading a relation that is not an adjecent table")
+
+	// note: a key that can not be bound is an error of the insert not a panic
+	e = orm.Insert(&station{ID: 1, Address: address{City: "Oslo"}})
+	var fe *morm.FieldError
+	AssertT(t, errors.Is(e, morm.ErrUnsupportedType) && errors.As(e, &fe) && fe.Field == "ID", e)
+}
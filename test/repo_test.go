@@ -0,0 +1,58 @@
+package test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	. "github.com/chapgx/assert/v2"
+	"github.com/chapgx/morm"
+)
+
+func TestRepo(t *testing.T) {
+	orm, e := morm.New(morm.SQLITE, filepath.Join(t.TempDir(), "repo.db"))
+	AssertT(t, e == nil, e)
+	defer orm.Close()
+
+	licenses, e := morm.NewRepo[pilotLicense](orm, "licenses")
+	AssertT(t, e == nil, e)
+	AssertT(t, licenses.Table() == "licenses", licenses.Table())
+
+	e = licenses.Create()
+	AssertT(t, e == nil, e)
+
+	for _, l := range []pilotLicense{{1, "Amelia"}, {2, "Bessie"}, {3, "Amelia"}} {
+		e = licenses.Insert(&l)
+		AssertT(t, e == nil, e)
+	}
+
+	l, e := licenses.Get(2)
+	AssertT(t, e == nil && l.Holder == "Bessie", l)
+
+	filter := morm.NewFilter()
+	filter.And("holder", morm.EQUAL, "Amelia")
+
+	list, e := licenses.List(&filter, morm.OrderBy(morm.Desc("id")))
+	AssertT(t, e == nil && len(list) == 2 && list[0].ID == 3, list)
+
+	l.Holder = "Charles"
+	byid := morm.NewFilter()
+	byid.And("id", morm.EQUAL, 2)
+	rslt := licenses.Update(&l, &byid, "Holder")
+	AssertT(t, rslt.Error == nil && rslt.RowsAffected == 1, rslt)
+
+	rslt = licenses.Delete(&filter)
+	AssertT(t, rslt.Error == nil && rslt.RowsAffected == 2, rslt)
+
+	count, e := licenses.Count(nil)
+	AssertT(t, e == nil && count == 1, count)
+
+	l, e = licenses.Get(2)
+	AssertT(t, e == nil && l.Holder == "Charles", l)
+
+	_, e = licenses.Get(1)
+	AssertT(t, errors.Is(e, morm.ErrNotFound), e)
+
+	_, e = morm.NewRepo[string](orm, "")
+	AssertT(t, e != nil, "expected an error creating a repo of a non struct type")
+}
@@ -0,0 +1,190 @@
+package test
+
+import (
+	"errors"
+	"maps"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/chapgx/assert/v2"
+	"github.com/chapgx/morm"
+)
+
+type invoice struct {
+	ID         int64  `morm:"id integer primary key"`
+	CustomerID string `morm:"customer_id text references customers(id)"`
+}
+
+type ledger struct {
+	ID        int64 `morm:"id integer primary key"`
+	InvoiceID int64 `morm:"invoice_id integer references invoices (id)"`
+}
+
+func TestSchemaTables(t *testing.T) {
+	orm, e := morm.New(morm.SQLITE, filepath.Join(t.TempDir(), "schema.db"))
+	AssertT(t, e == nil, e)
+	defer orm.Close()
+
+	rslt := orm.CreateTables(ledger{}, invoice{}, customer{})
+	AssertT(t, rslt.Error == nil, rslt.Error)
+	AssertT(t, maps.Equal(rslt.Tables, map[string]int64{"customers": 0, "address": 0, "invoices": 0, "ledgers": 0}), rslt.Tables)
+
+	e = orm.Insert(&customer{ID: "00", Name: "Ada", Address: address{City: "London"}})
+	AssertT(t, e == nil, e)
+	e = orm.Insert(&invoice{ID: 1, CustomerID: "00"})
+	AssertT(t, e == nil, e)
+
+	rslt = orm.TruncateTables(customer{}, invoice{})
+	AssertT(t, rslt.Error == nil, rslt.Error)
+	AssertT(t, maps.Equal(rslt.Tables, map[string]int64{"invoices": 1, "address": 1, "customers": 1}), rslt.Tables)
+	AssertT(t, rslt.RowsAffected == 3, rslt)
+
+	count, e := orm.Count(address{}, nil, "address")
+	AssertT(t, e == nil && count == 0, count)
+
+	rslt = orm.DropTables(customer{}, invoice{}, ledger{})
+	AssertT(t, rslt.Error == nil, rslt.Error)
+	AssertT(t, maps.Equal(rslt.Tables, map[string]int64{"ledgers": 0, "invoices": 0, "address": 0, "customers": 0}), rslt.Tables)
+
+	_, e = orm.Count(customer{}, nil, "")
+	AssertT(t, e != nil, "expected the customers table to be dropped")
+
+	e = orm.CreateTable(customer{}, "")
+	AssertT(t, e == nil, e)
+
+	e = orm.Drop(customer{})
+	AssertT(t, e == nil, e)
+
+	_, e = orm.Count(address{}, nil, "address")
+	AssertT(t, e != nil, "expected the adjecent address table to be dropped")
+}
+
+type gauge struct {
+	ID      int64 `morm:"id integer primary key"`
+	Reading float64
+	Signal  complex64
+}
+
+type meter struct {
+	ID      int64 `morm:"id integer primary key"`
+	Reading float64
+}
+
+type beacon struct {
+	ID      int64          `morm:"id integer primary key"`
+	Signals map[string]int `morm:"signals text"`
+}
+
+type probe struct {
+	ID int64 `morm:"id"`
+}
+
+type sensor struct {
+	ID    int64  `morm:"id integer primary key"`
+	Label string `morm:":flaten"`
+}
+
+type dial struct {
+	ID    int64  `morm:"id integer primary key"`
+	Label string `morm:":flatten"`
+}
+
+func TestSchemaErrors(t *testing.T) {
+	orm, e := morm.New(morm.SQLITE, filepath.Join(t.TempDir(), "errors.db"))
+	AssertT(t, e == nil, e)
+	defer orm.Close()
+
+	var fe *morm.FieldError
+
+	e = orm.CreateTable(gauge{}, "")
+	AssertT(t, errors.Is(e, morm.ErrUnsupportedType), e)
+	AssertT(t, errors.As(e, &fe) && fe.Model == "gauge" && fe.Field == "Signal", e)
+
+	// untagged floats are real columns
+	e = orm.CreateTable(meter{}, "")
+	AssertT(t, e == nil, e)
+	e = orm.Insert(&meter{ID: 1, Reading: 2.5})
+	AssertT(t, e == nil, e)
+
+	e = orm.CreateTable(beacon{}, "")
+	AssertT(t, e == nil, e)
+
+	e = orm.Insert(&beacon{ID: 1, Signals: map[string]int{"north": 1}})
+	AssertT(t, errors.Is(e, morm.ErrUnsupportedType), e)
+	AssertT(t, errors.As(e, &fe) && fe.Field == "Signals", e)
+
+	e = orm.Insert(nil)
+	AssertT(t, e != nil, "expected an error inserting nil")
+
+	var nilclient *morm.MORM
+	_, e = nilclient.Exec("select 1")
+	AssertT(t, errors.Is(e, morm.ErrDefaultClientIsNil), e)
+
+	rslt := nilclient.Delete(beacon{}, nil)
+	AssertT(t, errors.Is(rslt.Error, morm.ErrDefaultClientIsNil), rslt.Error)
+
+	e = nilclient.CreateTable(beacon{}, "")
+	AssertT(t, errors.Is(e, morm.ErrDefaultClientIsNil), e)
+	_, e = nilclient.Count(beacon{}, nil, "")
+	AssertT(t, errors.Is(e, morm.ErrDefaultClientIsNil), e)
+	_, e = nilclient.Info()
+	AssertT(t, errors.Is(e, morm.ErrDefaultClientIsNil), e)
+	rslt = nilclient.CreateTables(beacon{})
+	AssertT(t, errors.Is(rslt.Error, morm.ErrDefaultClientIsNil), rslt.Error)
+	rslt = nilclient.Guarded().UpdateExpr(beacon{}, nil, morm.Inc("id", 1))
+	AssertT(t, errors.Is(rslt.Error, morm.ErrDefaultClientIsNil), rslt.Error)
+
+	// note: a tag without a column type is reported instead of read past its end
+	e = orm.CreateTable(probe{}, "")
+	AssertT(t, errors.Is(e, morm.ErrInvalidTag) && errors.As(e, &fe) && fe.Field == "ID", e)
+	e = orm.Insert(&probe{ID: 1})
+	AssertT(t, errors.Is(e, morm.ErrInvalidTag), e)
+	var probes []probe
+	e = orm.Read(&probes, nil, "")
+	AssertT(t, errors.Is(e, morm.ErrInvalidTag), e)
+
+	// note: unknown directives and flattened fields that are not structs are not turned into columns
+	for _, model := range []any{sensor{}, dial{}} {
+		e = orm.CreateTable(model, "")
+		AssertT(t, errors.Is(e, morm.ErrInvalidTag) && errors.As(e, &fe) && fe.Field == "Label", e)
+	}
+	e = orm.Insert(&sensor{ID: 1})
+	AssertT(t, errors.Is(e, morm.ErrInvalidTag), e)
+	var dials []dial
+	e = orm.Read(&dials, nil, "")
+	AssertT(t, errors.Is(e, morm.ErrInvalidTag), e)
+
+	rslt = morm.InsertMany(orm, []*meter{{ID: 2}, nil}, "")
+	AssertT(t, rslt.Error != nil && strings.Contains(rslt.Error.Error(), "record 1"), rslt.Error)
+
+	// note: models that are nil or not structs are unsupported
+	filter := morm.NewFilter()
+	filter.And("id", morm.EQUAL, 1)
+
+	var n int
+	var nilmeter *meter
+	var meters []meter
+	_, counterr := orm.Count(nil, nil, "")
+	_, pageerr := orm.ReadPage(&[]int{}, nil, "", morm.PageRequest{Keys: []morm.Order{morm.Asc("id")}, Size: 1})
+	for _, e := range []error{
+		orm.Save(nil).Error,
+		orm.Save(nilmeter).Error,
+		orm.Delete(nil, &filter).Error,
+		orm.DeleteModel(nil).Error,
+		orm.Update(nil, &filter, "Reading").Error,
+		orm.Update(&n, &filter, "Reading").Error,
+		orm.UpdateExpr(nil, &filter, morm.Inc("id", 1)).Error,
+		orm.Guarded().Delete(nil, &filter).Error,
+		orm.CreateTable(nil, ""),
+		orm.Drop(nil),
+		orm.Insert(&n),
+		orm.FindByID(&n, 1, ""),
+		orm.First(&n, nil, ""),
+		orm.Read(&meters, nil, "", morm.FromModel(1)),
+		counterr,
+		pageerr,
+	} {
+		AssertT(t, errors.Is(e, morm.ErrUnsupportedType), e)
+	}
+}
@@ -0,0 +1,464 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	. "github.com/chapgx/assert/v2"
+	"github.com/chapgx/morm"
+)
+
+type planeRegistration struct {
+	ID    int64  `morm:"id integer primary key autoincrement"`
+	Tail  string `morm:"tail text unique"`
+	Owner string `morm:"owner text"`
+	Hours int    `morm:"hours int"`
+}
+
+func TestUpsert(t *testing.T) {
+	orm, e := morm.New(morm.SQLITE, filepath.Join(t.TempDir(), "upsert.db"))
+	AssertT(t, e == nil, e)
+	defer orm.Close()
+
+	e = orm.CreateTable(pilotLicense{}, "")
+	AssertT(t, e == nil, e)
+
+	rslt := orm.Save(&pilotLicense{1, "Amelia"})
+	AssertT(t, rslt.Error == nil && rslt.RowsAffected == 1, rslt)
+
+	rslt = orm.Save(&pilotLicense{1, "Bessie"})
+	AssertT(t, rslt.Error == nil && rslt.RowsAffected == 1, rslt)
+
+	var l pilotLicense
+	e = orm.FindByID(&l, 1, "")
+	AssertT(t, e == nil && l.Holder == "Bessie", l)
+
+	e = orm.CreateTable(planeRegistration{}, "")
+	AssertT(t, e == nil, e)
+
+	// autoincrement key not set is an insert
+	rslt = orm.Save(&planeRegistration{Tail: "N123", Owner: "Amelia", Hours: 10})
+	AssertT(t, rslt.Error == nil, rslt.Error)
+
+	rslt = orm.UpsertWith(&planeRegistration{ID: 9, Tail: "N123", Owner: "Bessie", Hours: 25}, morm.UpsertOptions{
+		Conflict: []string{"tail"},
+		Update:   []string{"hours"},
+	})
+	AssertT(t, rslt.Error == nil && rslt.RowsAffected == 1, rslt)
+
+	rslt = orm.UpsertWith(&planeRegistration{ID: 9, Tail: "N123", Owner: "Charles"}, morm.UpsertOptions{
+		Conflict: []string{"tail"},
+		Ignore:   true,
+	})
+	AssertT(t, rslt.Error == nil && rslt.RowsAffected == 0, rslt)
+
+	var registrations []planeRegistration
+	e = orm.Read(&registrations, nil, "")
+	AssertT(t, e == nil && len(registrations) == 1, registrations)
+
+	r := registrations[0]
+	AssertT(t, r.ID == 1 && r.Owner == "Amelia" && r.Hours == 25, r)
+
+	rslt = orm.Upsert(&planeRegistration{Tail: "N123"}, "registration")
+	AssertT(t, errors.Is(rslt.Error, morm.ErrUnmappedField), rslt.Error)
+
+	rslt = orm.Save(&plane{})
+	AssertT(t, errors.Is(rslt.Error, morm.ErrNoPrimaryKey), rslt.Error)
+
+	// note: an insert through Save reports its outcome and writes only the record table
+	rslt = orm.Save(&hangar{Name: "North"})
+	AssertT(t, rslt.Error != nil && rslt.RowsAffected == 0, rslt)
+
+	e = orm.CreateTable(hangar{}, "")
+	AssertT(t, e == nil, e)
+
+	h := hangar{Name: "North", Keeper: hangarKeeper{Name: "Ada"}}
+	rslt = orm.Save(&h)
+	AssertT(t, rslt.Error == nil && rslt.RowsAffected == 1 && h.ID == 1, h)
+
+	count, e := orm.Count(hangarKeeper{}, nil, "")
+	AssertT(t, e == nil && count == 0, count)
+}
+
+type hangar struct {
+	ID     int64  `morm:"id integer primary key autoincrement"`
+	Name   string `morm:"name text"`
+	Keeper hangarKeeper
+}
+
+type hangarKeeper struct {
+	ID   int64  `morm:"id integer primary key autoincrement"`
+	Name string `morm:"name text"`
+}
+
+func TestInsertGeneratedKeys(t *testing.T) {
+	orm, e := morm.New(morm.SQLITE, filepath.Join(t.TempDir(), "generated.db"))
+	AssertT(t, e == nil, e)
+	defer orm.Close()
+
+	e = orm.CreateTable(hangar{}, "")
+	AssertT(t, e == nil, e)
+
+	for i, name := range []string{"North", "South"} {
+		h := hangar{Name: name, Keeper: hangarKeeper{Name: name + " Keeper"}}
+		e = orm.Insert(&h)
+		AssertT(t, e == nil, e)
+		AssertT(t, h.ID == int64(i+1) && h.Keeper.ID == int64(i+1), h)
+	}
+
+	var got hangar
+	e = orm.FindByID(&got, 2, "", morm.WithRelations("Keeper"))
+	AssertT(t, e == nil, e)
+	AssertT(t, got.Name == "South" && got.Keeper.Name == "South Keeper" && got.Keeper.ID == 2, got)
+}
+
+func TestInsertMany(t *testing.T) {
+	orm, e := morm.New(morm.SQLITE, filepath.Join(t.TempDir(), "many.db"))
+	AssertT(t, e == nil, e)
+	defer orm.Close()
+
+	e = orm.CreateTable(planeRegistration{}, "")
+	AssertT(t, e == nil, e)
+
+	registrations := make([]planeRegistration, 1200)
+	for i := range registrations {
+		registrations[i] = planeRegistration{Tail: fmt.Sprintf("N%04d", i), Owner: "Amelia", Hours: i}
+	}
+
+	var progress []int
+	rslt := morm.InsertMany(orm, registrations, "", morm.WithProgress(func(done, total int) {
+		AssertT(t, total == 1200, total)
+		progress = append(progress, done)
+	}))
+	AssertT(t, rslt.Error == nil && rslt.RowsAffected == 1200, rslt)
+	AssertT(t, len(progress) == 4 && progress[3] == 1200, progress)
+
+	count, e := orm.Count(planeRegistration{}, nil, "")
+	AssertT(t, e == nil && count == 1200, count)
+
+	// note: a failing chunk rolls back every chunk
+	duplicates := []planeRegistration{{Tail: "N9999"}, {Tail: "N0001"}}
+	rslt = morm.InsertMany(orm, duplicates, "", morm.ChunkSize(1))
+	AssertT(t, rslt.Error != nil, "expected a unique constraint error")
+
+	count, e = orm.Count(planeRegistration{}, nil, "")
+	AssertT(t, e == nil && count == 1200, count)
+
+	e = orm.CreateTable(hangar{}, "")
+	AssertT(t, e == nil, e)
+
+	hangars := []hangar{{Name: "North", Keeper: hangarKeeper{Name: "Ada"}}, {Name: "South", Keeper: hangarKeeper{Name: "Grace"}}}
+	rslt = morm.InsertMany(orm, hangars, "")
+	AssertT(t, rslt.Error == nil && rslt.RowsAffected == 2, rslt)
+	AssertT(t, hangars[1].ID == 2 && hangars[1].Keeper.ID == 2, hangars)
+}
+
+func TestUpdateMany(t *testing.T) {
+	orm, e := morm.New(morm.SQLITE, filepath.Join(t.TempDir(), "updatemany.db"))
+	AssertT(t, e == nil, e)
+	defer orm.Close()
+
+	e = orm.CreateTable(planeRegistration{}, "")
+	AssertT(t, e == nil, e)
+
+	registrations := []planeRegistration{{Tail: "N1", Owner: "Ada"}, {Tail: "N2", Owner: "Grace"}, {Tail: "N3", Owner: "Linus"}}
+	rslt := morm.InsertMany(orm, registrations, "")
+	AssertT(t, rslt.Error == nil, rslt.Error)
+
+	var stored []planeRegistration
+	e = orm.Read(&stored, nil, "", morm.OrderBy(morm.Asc("id")))
+	AssertT(t, e == nil && len(stored) == 3, stored)
+
+	for i := range stored {
+		stored[i].Hours = (i + 1) * 10
+		stored[i].Owner = "Bessie"
+	}
+
+	rslt = morm.UpdateMany(orm, stored[:2], "", []string{"Hours"}, morm.ChunkSize(1))
+	AssertT(t, rslt.Error == nil && rslt.RowsAffected == 2, rslt)
+
+	var updated []planeRegistration
+	e = orm.Read(&updated, nil, "", morm.OrderBy(morm.Asc("id")))
+	AssertT(t, e == nil, e)
+	AssertT(t, updated[0].Hours == 10 && updated[1].Hours == 20 && updated[2].Hours == 0, updated)
+	AssertT(t, updated[0].Owner == "Ada", updated[0])
+
+	rslt = morm.UpdateMany(orm, stored, "", nil)
+	AssertT(t, rslt.Error == nil && rslt.RowsAffected == 3, rslt)
+
+	e = orm.Read(&updated, nil, "", morm.OrderBy(morm.Asc("id")))
+	AssertT(t, e == nil && updated[2].Hours == 30 && updated[2].Owner == "Bessie", updated)
+
+	rslt = morm.UpdateMany(orm, stored, "", []string{"Wingspan"})
+	AssertT(t, errors.Is(rslt.Error, morm.ErrUnmappedField), rslt.Error)
+}
+
+func TestUpdateExpr(t *testing.T) {
+	orm, e := morm.New(morm.SQLITE, filepath.Join(t.TempDir(), "expr.db"))
+	AssertT(t, e == nil, e)
+	defer orm.Close()
+
+	e = orm.CreateTable(planeRegistration{}, "")
+	AssertT(t, e == nil, e)
+
+	rslt := morm.InsertMany(orm, []planeRegistration{{Tail: "N1", Owner: "Ada", Hours: 5}, {Tail: "N2", Owner: "Grace", Hours: 7}}, "")
+	AssertT(t, rslt.Error == nil, rslt.Error)
+
+	filter := morm.NewFilter()
+	filter.And("tail", morm.EQUAL, "N1")
+
+	rslt = orm.UpdateExpr(planeRegistration{}, &filter, morm.Inc("hours", 2))
+	AssertT(t, rslt.Error == nil && rslt.RowsAffected == 1, rslt)
+
+	rslt = orm.UpdateExpr(planeRegistration{}, nil, morm.SetExpr("hours", "hours * 10"), morm.SetColumn("owner", "tail"))
+	AssertT(t, rslt.Error == nil && rslt.RowsAffected == 2, rslt)
+
+	var registrations []planeRegistration
+	e = orm.Read(&registrations, nil, "", morm.OrderBy(morm.Asc("id")))
+	AssertT(t, e == nil, e)
+	AssertT(t, registrations[0].Hours == 70 && registrations[0].Owner == "N1", registrations[0])
+	AssertT(t, registrations[1].Hours == 70 && registrations[1].Owner == "N2", registrations[1])
+
+	rslt = orm.UpdateExpr(planeRegistration{}, &filter, morm.Set("owner", morm.Now()), morm.Inc("hours", -1))
+	AssertT(t, rslt.Error == nil && rslt.RowsAffected == 1, rslt)
+
+	rslt = orm.UpdateExpr(planeRegistration{}, &filter)
+	AssertT(t, rslt.Error != nil, "expected an error updating without assignments")
+}
+
+type crewMember struct {
+	ID      int64   `morm:"id integer primary key"`
+	Name    string  `morm:"name text"`
+	Contact contact `morm:":flatten"`
+}
+
+func TestUpdateChanged(t *testing.T) {
+	orm, e := morm.New(morm.SQLITE, filepath.Join(t.TempDir(), "tracking.db"))
+	AssertT(t, e == nil, e)
+	defer orm.Close()
+
+	e = orm.CreateTable(crewMember{}, "")
+	AssertT(t, e == nil, e)
+
+	for _, c := range []crewMember{{ID: 1, Name: "Ada"}, {ID: 2, Name: "Grace"}} {
+		e = orm.Insert(&c)
+		AssertT(t, e == nil, e)
+	}
+
+	var crew []crewMember
+	e = orm.Read(&crew, nil, "", morm.Tracked(), morm.OrderBy(morm.Asc("id")))
+	AssertT(t, e == nil && len(crew) == 2, crew)
+
+	rslt := orm.UpdateChanged(&crew[0])
+	AssertT(t, rslt.Error == nil && rslt.RowsAffected == 0, rslt)
+
+	crew[0].Contact.Number = "555-0100"
+	crew[0].Contact.Ext.Active = true
+	rslt = orm.UpdateChanged(&crew[0])
+	AssertT(t, rslt.Error == nil && rslt.RowsAffected == 1, rslt)
+
+	// note: the snapshot is refreshed after the update
+	rslt = orm.UpdateChanged(&crew[0])
+	AssertT(t, rslt.Error == nil && rslt.RowsAffected == 0, rslt)
+
+	var ada crewMember
+	e = orm.FindByID(&ada, 1, "", morm.Tracked())
+	AssertT(t, e == nil && ada.Contact.Number == "555-0100" && ada.Contact.Ext.Active, ada)
+
+	var grace crewMember
+	e = orm.FindByID(&grace, 2, "")
+	AssertT(t, e == nil && grace.Contact.Number == "", grace)
+
+	rslt = orm.UpdateChanged(&grace)
+	AssertT(t, errors.Is(rslt.Error, morm.ErrNotTracked), rslt.Error)
+
+	orm.Untrack(&crew[1])
+	rslt = orm.UpdateChanged(&crew[1])
+	AssertT(t, errors.Is(rslt.Error, morm.ErrNotTracked), rslt.Error)
+
+	orm.UntrackAll()
+	rslt = orm.UpdateChanged(&ada)
+	AssertT(t, errors.Is(rslt.Error, morm.ErrNotTracked), rslt.Error)
+
+	e = orm.CreateTable(plane{}, "")
+	AssertT(t, e == nil, e)
+
+	e = orm.Read(&[]plane{}, nil, "", morm.Tracked())
+	AssertT(t, errors.Is(e, morm.ErrNoPrimaryKey), e)
+}
+
+func TestUpdateFieldPaths(t *testing.T) {
+	orm, e := morm.New(morm.SQLITE, filepath.Join(t.TempDir(), "paths.db"))
+	AssertT(t, e == nil, e)
+	defer orm.Close()
+
+	e = orm.CreateTable(crewMember{}, "crew")
+	AssertT(t, e == nil, e)
+
+	e = orm.InsertByName(&crewMember{ID: 1, Name: "Ada"}, "crew")
+	AssertT(t, e == nil, e)
+
+	filter := morm.NewFilter()
+	filter.And("id", morm.EQUAL, 1)
+
+	changes := crewMember{Name: "Ada L", Contact: contact{Number: "555-0100", Ext: extension{Code: "12"}}}
+	rslt := orm.UpdateByName(&changes, "crew", &filter, "Name", "Contact.Number", "Contact.Ext.Code")
+	AssertT(t, rslt.Error == nil && rslt.RowsAffected == 1, rslt)
+
+	var ada crewMember
+	e = orm.FindByID(&ada, 1, "crew")
+	AssertT(t, e == nil, e)
+	AssertT(t, ada.Name == "Ada L" && ada.Contact.Number == "555-0100" && ada.Contact.Ext.Code == "12", ada)
+
+	rslt = orm.UpdateByName(&changes, "crew", &filter, "Contact.Fax")
+	AssertT(t, errors.Is(rslt.Error, morm.ErrUnmappedField), rslt.Error)
+
+	e = orm.CreateTable(customer{}, "")
+	AssertT(t, e == nil, e)
+
+	for _, c := range []customer{{ID: "00", Name: "Ada", Address: address{City: "London"}}, {ID: "01", Name: "Grace", Address: address{City: "Arlington"}}} {
+		e = orm.Insert(&c)
+		AssertT(t, e == nil, e)
+	}
+
+	byid := morm.NewFilter()
+	byid.And("id", morm.EQUAL, "01")
+
+	rslt = orm.Update(&customer{Name: "Grace H", Address: address{City: "New York"}}, &byid, "Name", "Address.City")
+	AssertT(t, rslt.Error == nil && rslt.RowsAffected == 2, rslt)
+	AssertT(t, rslt.Tables["customers"] == 1 && rslt.Tables["address"] == 1, rslt.Tables)
+
+	// note: guards count the rows of the customers table not the adjecent address rows
+	rslt = orm.Guarded(morm.ExpectRows(1)).Update(&customer{Name: "Grace H", Address: address{City: "New York"}}, &byid, "Name", "Address.City")
+	AssertT(t, rslt.Error == nil && rslt.RowsAffected == 2, rslt)
+
+	var customers []customer
+	e = orm.Read(&customers, nil, "", morm.WithRelations("Address"), morm.OrderBy(morm.Asc("id")))
+	AssertT(t, e == nil, e)
+	AssertT(t, customers[0].Address.City == "London", customers[0])
+	AssertT(t, customers[1].Name == "Grace H" && customers[1].Address.City == "New York", customers[1])
+}
+
+func TestDeleteModel(t *testing.T) {
+	orm, e := morm.New(morm.SQLITE, filepath.Join(t.TempDir(), "deletemodel.db"))
+	AssertT(t, e == nil, e)
+	defer orm.Close()
+
+	e = orm.CreateTable(hangar{}, "")
+	AssertT(t, e == nil, e)
+
+	hangars := []hangar{{Name: "North", Keeper: hangarKeeper{Name: "Ada"}}, {Name: "South", Keeper: hangarKeeper{Name: "Grace"}}}
+	for i := range hangars {
+		e = orm.Insert(&hangars[i])
+		AssertT(t, e == nil, e)
+	}
+
+	rslt := orm.DeleteModel(&hangars[0], morm.Cascade())
+	AssertT(t, rslt.Error == nil && rslt.RowsAffected == 2, rslt)
+	AssertT(t, rslt.Tables["hangars"] == 1 && rslt.Tables["hangarkeepers"] == 1, rslt.Tables)
+
+	keepers, e := orm.Count(hangarKeeper{}, nil, "")
+	AssertT(t, e == nil && keepers == 1, keepers)
+
+	rslt = orm.DeleteModel(hangars[1])
+	AssertT(t, rslt.Error == nil && rslt.RowsAffected == 1 && len(rslt.Tables) == 1, rslt)
+
+	keepers, e = orm.Count(hangarKeeper{}, nil, "")
+	AssertT(t, e == nil && keepers == 1, keepers)
+
+	rslt = orm.DeleteModel(plane{})
+	AssertT(t, errors.Is(rslt.Error, morm.ErrNoPrimaryKey), rslt.Error)
+}
+
+func TestGuarded(t *testing.T) {
+	orm, e := morm.New(morm.SQLITE, filepath.Join(t.TempDir(), "guarded.db"))
+	AssertT(t, e == nil, e)
+	defer orm.Close()
+
+	e = orm.CreateTable(planeRegistration{}, "")
+	AssertT(t, e == nil, e)
+
+	rslt := morm.InsertMany(orm, []planeRegistration{{Tail: "N1", Owner: "Ada"}, {Tail: "N2", Owner: "Ada"}, {Tail: "N3", Owner: "Grace"}}, "")
+	AssertT(t, rslt.Error == nil, rslt.Error)
+
+	filter := morm.NewFilter()
+	filter.And("owner", morm.EQUAL, "Ada")
+
+	rslt = orm.Guarded(morm.ExpectRows(1)).Update(&planeRegistration{Hours: 9}, &filter, "Hours")
+	var guarderr *morm.GuardError
+	AssertT(t, errors.As(rslt.Error, &guarderr) && guarderr.Guard == "ExpectRows" && guarderr.Affected == 2, rslt.Error)
+	AssertT(t, errors.Is(rslt.Error, morm.ErrGuardViolated), rslt.Error)
+
+	// note: the violating update was rolled back
+	hours := morm.NewFilter()
+	hours.And("hours", morm.EQUAL, 9)
+	count, e := orm.Count(planeRegistration{}, &hours, "")
+	AssertT(t, e == nil && count == 0, count)
+
+	rslt = orm.Guarded(morm.MaxRows(2)).Update(&planeRegistration{Hours: 9}, &filter, "Hours")
+	AssertT(t, rslt.Error == nil && rslt.RowsAffected == 2, rslt)
+
+	rslt = orm.Guarded().UpdateExpr(planeRegistration{}, nil, morm.Inc("hours", 1))
+	AssertT(t, errors.Is(rslt.Error, morm.ErrGuardViolated), rslt.Error)
+
+	rslt = orm.Guarded(morm.UpdateAll(), morm.ExpectRows(3)).UpdateExpr(planeRegistration{}, nil, morm.Inc("hours", 1))
+	AssertT(t, rslt.Error == nil && rslt.RowsAffected == 3, rslt)
+
+	rslt = orm.Guarded(morm.MaxRows(1)).Delete(planeRegistration{}, &filter)
+	AssertT(t, errors.As(rslt.Error, &guarderr) && guarderr.Guard == "MaxRows", rslt.Error)
+
+	rslt = orm.Guarded().Delete(planeRegistration{}, nil)
+	AssertT(t, errors.As(rslt.Error, &guarderr) && guarderr.Guard == "DeleteAll", rslt.Error)
+
+	rslt = orm.Guarded(morm.DeleteAll()).Delete(planeRegistration{}, nil)
+	AssertT(t, rslt.Error == nil && rslt.RowsAffected == 3, rslt)
+}
+
+func TestParameterizedValues(t *testing.T) {
+	orm, e := morm.New(morm.SQLITE, filepath.Join(t.TempDir(), "params.db"))
+	AssertT(t, e == nil, e)
+	defer orm.Close()
+
+	e = orm.CreateTable(planeRegistration{}, "")
+	AssertT(t, e == nil, e)
+
+	r := planeRegistration{Tail: "N'1", Owner: "O'Brien", Hours: 3}
+	e = orm.Insert(&r)
+	AssertT(t, e == nil, e)
+
+	filter := morm.NewFilter()
+	filter.And("owner", morm.EQUAL, "O'Brien")
+
+	var found planeRegistration
+	e = orm.First(&found, &filter, "")
+	AssertT(t, e == nil && found.Tail == "N'1", found)
+
+	wsql, e := filter.WhereSQL()
+	AssertT(t, e == nil && wsql == "where owner = 'O''Brien'", wsql)
+
+	// note: placeholders in comments and quoted identifiers are not bound
+	commented := morm.NewFilter()
+	commented.And("owner /* ? */", morm.EQUAL, "O'Brien")
+	commented.And("[tail ?]", morm.EQUAL, "N'1")
+	wsql, e = commented.WhereSQL()
+	AssertT(t, e == nil && wsql == "where owner /* ? */ = 'O''Brien' and [tail ?] = 'N''1'", wsql)
+
+	r.Owner = "Miles O'Brien"
+	rslt := orm.Update(&r, &filter, "Owner")
+	AssertT(t, rslt.Error == nil && rslt.RowsAffected == 1, rslt)
+
+	// note: values are bound so they are never read as sql
+	injection := morm.NewFilter()
+	injection.And("owner", morm.EQUAL, "x' or '1'='1")
+
+	n, e := orm.Count(planeRegistration{}, &injection, "")
+	AssertT(t, e == nil && n == 0, n)
+
+	rslt = orm.Delete(planeRegistration{}, &injection)
+	AssertT(t, rslt.Error == nil && rslt.RowsAffected == 0, rslt)
+
+	n, e = orm.Count(planeRegistration{}, nil, "")
+	AssertT(t, e == nil && n == 1, n)
+}
@@ -0,0 +1,169 @@
+package morm
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// snapshot is the state of a tracked record when it was read
+type snapshot struct {
+	tablename string
+	pk        column
+	columns   []column
+	values    []any
+}
+
+// track records a snapshot of every record, records must be addressable
+func (m *MORM) track(records []reflect.Value, t reflect.Type, tablename string, columns []column, opts read_options) error {
+	source := t
+	if opts.source != nil {
+		source = opts.source
+	}
+
+	if tablename == "" {
+		tablename = default_tablename(source)
+	}
+
+	pk, ok := primary_key(source)
+	if !ok {
+		return fmt.Errorf("%w: %s records can not be tracked", ErrNoPrimaryKey, source.Name())
+	}
+
+	// note: the key is looked up in the read columns so projections are tracked by their own field
+	found := false
+	for _, c := range columns {
+		if c.name == pk.name {
+			pk, found = c, true
+			break
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("%w: %s primary key is not read, records can not be tracked", ErrNoPrimaryKey, t.Name())
+	}
+
+	m.snapshotsmu.Lock()
+	defer m.snapshotsmu.Unlock()
+
+	if m.snapshots == nil {
+		m.snapshots = make(map[any]snapshot)
+	}
+
+	for _, v := range records {
+		values, e := snapshot_values(v, columns)
+		if e != nil {
+			return e
+		}
+		m.snapshots[v.Addr().Interface()] = snapshot{tablename: tablename, pk: pk, columns: columns, values: values}
+	}
+
+	return nil
+}
+
+// Untrack discards the snapshot of a tracked record, model is the address the record was read into
+func (m *MORM) Untrack(model any) {
+	if m == nil {
+		return
+	}
+
+	m.snapshotsmu.Lock()
+	defer m.snapshotsmu.Unlock()
+	delete(m.snapshots, model)
+}
+
+// UntrackAll discards the snapshots of every tracked record, for example once a batch of records read with
+// [Tracked] was processed
+func (m *MORM) UntrackAll() {
+	if m == nil {
+		return
+	}
+
+	m.snapshotsmu.Lock()
+	defer m.snapshotsmu.Unlock()
+	clear(m.snapshots)
+}
+
+// UpdateChanged updates by primary key the columns of a record read with [Tracked] that changed since it was read
+// or last updated, flattened fields included. Nothing is executed when no column changed.
+//
+// model is the address the record was read into, for example &records[i]
+func (m *MORM) UpdateChanged(model any) Result {
+	if m == nil {
+		return error_result(ErrDefaultClientIsNil)
+	}
+
+	v := reflect.ValueOf(model)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return error_result(ErrReadTargetNotPointer)
+	}
+
+	m.snapshotsmu.Lock()
+	snap, found := m.snapshots[model]
+	m.snapshotsmu.Unlock()
+
+	if !found {
+		return error_result(fmt.Errorf("%w: %s", ErrNotTracked, v.Elem().Type()))
+	}
+
+	values, e := snapshot_values(v.Elem(), snap.columns)
+	if e != nil {
+		return error_result(e)
+	}
+
+	var sets []string
+	var args []any
+	for i, c := range snap.columns {
+		if !reflect.DeepEqual(values[i], snap.values[i]) {
+			sets = append(sets, c.name+"=?")
+			args = append(args, values[i])
+		}
+	}
+
+	if len(sets) == 0 {
+		return new_result(nil, 0)
+	}
+
+	// note: the record is matched by its key as it was read in case the key changed
+	var key any
+	for i, c := range snap.columns {
+		if c.name == snap.pk.name {
+			key = snap.values[i]
+		}
+	}
+
+	query := statement{
+		query: fmt.Sprintf("update %s\nset %s\nwhere %s = ?;", snap.tablename, strings.Join(sets, ","), snap.pk.name),
+		args:  append(args, key),
+	}
+	rslt := exec_write(m, query)
+	if rslt.Error != nil {
+		return rslt
+	}
+
+	snap.values = values
+	m.snapshotsmu.Lock()
+	m.snapshots[model] = snap
+	m.snapshotsmu.Unlock()
+
+	return rslt
+}
+
+// snapshot_values returns the values of the columns of v as they are bound to queries
+func snapshot_values(v reflect.Value, columns []column) ([]any, error) {
+	values := make([]any, len(columns))
+	for i, c := range columns {
+		value, e := field_arg(v.FieldByIndex(c.index), c.field.Type, c.tag)
+		if e != nil {
+			return nil, err_wrap(e, fmt.Sprintf("unable to bind %s", c.name))
+		}
+
+		// note: bytes are copied so changes in place are not reflected in the snapshot
+		if b, ok := value.([]byte); ok {
+			value = slices.Clone(b)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
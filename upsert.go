@@ -0,0 +1,237 @@
+package morm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// UpsertOptions configures how [MORM.UpsertWith] resolves conflicts
+type UpsertOptions struct {
+	// Table is the table name, derived from the model when empty
+	Table string
+
+	// Conflict are the columns that identify an existing record, the primary key when empty.
+	// MySQL ignores them and uses any unique key of the table
+	Conflict []string
+
+	// Update are the columns updated on conflict, every column but the conflict columns when empty
+	Update []string
+
+	// Ignore leaves the existing record untouched on conflict
+	Ignore bool
+}
+
+// Save inserts the model or updates every column of the existing record with the same primary key.
+//
+// A model with an autoincrement primary key that is not set is inserted and the generated key is written back
+// when model is a pointer. Only the record table is written, adjecent tables are not
+func (m *MORM) Save(model any) Result {
+	if m == nil {
+		return error_result(ErrDefaultClientIsNil)
+	}
+
+	return upsert(model, UpsertOptions{}, m)
+}
+
+// Upsert inserts the model or updates the existing record conflicting on conflictColumns,
+// the primary key when none is passed
+func (m *MORM) Upsert(model any, conflictColumns ...string) Result {
+	if m == nil {
+		return error_result(ErrDefaultClientIsNil)
+	}
+
+	return upsert(model, UpsertOptions{Conflict: conflictColumns}, m)
+}
+
+// UpsertWith inserts the model or resolves the conflict with an existing record as opts declares
+func (m *MORM) UpsertWith(model any, opts UpsertOptions) Result {
+	if m == nil {
+		return error_result(ErrDefaultClientIsNil)
+	}
+
+	return upsert(model, opts, m)
+}
+
+// upsert renders INSERT ... ON CONFLICT on SQLITE, MERGE on SQL Server and ON DUPLICATE KEY UPDATE on MySQL.
+// Only the record table is written, adjecent tables are not
+func upsert(model any, opts UpsertOptions, m *MORM) Result {
+	t, e := pulltype(model)
+	if e != nil {
+		return error_result(e)
+	}
+	v := pullvalue(model)
+
+	// note: generated columns that are not set are left to the database
+	modelcolumns, e := model_columns(t)
+	if e != nil {
+		return error_result(e)
+	}
+
+	var columns []column
+	for _, c := range modelcolumns {
+		if is_generated(c.tag) && v.FieldByIndex(c.index).IsZero() {
+			continue
+		}
+		columns = append(columns, c)
+	}
+
+	byname := make(map[string]column, len(columns))
+	for _, c := range columns {
+		byname[c.name] = c
+	}
+
+	conflict := opts.Conflict
+	if len(conflict) == 0 {
+		pk, ok := primary_key(t)
+		if !ok {
+			return error_result(fmt.Errorf("%w: %s conflict columns are required", ErrNoPrimaryKey, t.Name()))
+		}
+
+		// note: an autoincrement key that is not set can't conflict, the database generates it
+		if is_generated(pk.tag) && v.FieldByIndex(pk.index).IsZero() {
+			return upsert_insert(model, opts.Table, m)
+		}
+
+		conflict = []string{pk.name}
+	}
+
+	isconflict := make(map[string]bool, len(conflict))
+	for _, name := range conflict {
+		if _, found := byname[name]; !found {
+			return error_result(fmt.Errorf("%w: conflict column %s in %s", ErrUnmappedField, name, t.Name()))
+		}
+		isconflict[name] = true
+	}
+
+	updates := opts.Update
+	if len(updates) == 0 {
+		for _, c := range columns {
+			if !isconflict[c.name] {
+				updates = append(updates, c.name)
+			}
+		}
+	}
+
+	for _, name := range updates {
+		if _, found := byname[name]; !found {
+			return error_result(fmt.Errorf("%w: update column %s in %s", ErrUnmappedField, name, t.Name()))
+		}
+	}
+
+	names := make([]string, 0, len(columns))
+	values := make([]any, 0, len(columns))
+	for _, c := range columns {
+		value, e := field_arg(v.FieldByIndex(c.index), c.field.Type, c.tag)
+		if e != nil {
+			return error_result(err_wrap(e, fmt.Sprintf("unable to bind %s", c.name)))
+		}
+		names = append(names, c.name)
+		values = append(values, value)
+	}
+
+	tablename := opts.Table
+	if tablename == "" {
+		tablename = default_tablename(t)
+	}
+
+	ignore := opts.Ignore || len(updates) == 0
+
+	// note: every renderer binds the values once in the order of names
+	query := statement{args: values}
+	switch m.engine {
+	case SQLITE:
+		query.query = sqlite_upsert_query(tablename, names, conflict, updates, ignore)
+	case SQLServer:
+		query.query = mssql_merge_query(tablename, names, conflict, updates, ignore)
+	case MySQL:
+		query.query = mysql_upsert_query(tablename, names, updates, ignore)
+	default:
+		return error_result(fmt.Errorf("%w: %s upserts", ErrUnsupportedEngine, m.engine))
+	}
+
+	return exec_write(m, query)
+}
+
+func sqlite_upsert_query(table string, names, conflict, updates []string, ignore bool) string {
+	query := fmt.Sprintf("insert into %s(%s)\nvalues (%s)\non conflict(%s) ", table, strings.Join(names, ", "), placeholders(len(names)), strings.Join(conflict, ", "))
+	if ignore {
+		return query + "do nothing;"
+	}
+
+	sets := make([]string, len(updates))
+	for i, name := range updates {
+		sets[i] = fmt.Sprintf("%s = excluded.%s", name, name)
+	}
+	return query + "do update set " + strings.Join(sets, ", ") + ";"
+}
+
+func mssql_merge_query(table string, names, conflict, updates []string, ignore bool) string {
+	selection := make([]string, len(names))
+	sourcenames := make([]string, len(names))
+	for i, name := range names {
+		selection[i] = "? as " + name
+		sourcenames[i] = "source." + name
+	}
+
+	on := make([]string, len(conflict))
+	for i, name := range conflict {
+		on[i] = fmt.Sprintf("target.%s = source.%s", name, name)
+	}
+
+	query := fmt.Sprintf("merge into %s with (holdlock) as target\nusing (select %s) as source\non (%s)\n", table, strings.Join(selection, ", "), strings.Join(on, " and "))
+	if !ignore {
+		sets := make([]string, len(updates))
+		for i, name := range updates {
+			sets[i] = fmt.Sprintf("target.%s = source.%s", name, name)
+		}
+		query += fmt.Sprintf("when matched then update set %s\n", strings.Join(sets, ", "))
+	}
+
+	return query + fmt.Sprintf("when not matched then insert (%s) values (%s);", strings.Join(names, ", "), strings.Join(sourcenames, ", "))
+}
+
+func mysql_upsert_query(table string, names, updates []string, ignore bool) string {
+	if ignore {
+		return fmt.Sprintf("insert ignore into %s(%s)\nvalues (%s);", table, strings.Join(names, ", "), placeholders(len(names)))
+	}
+
+	sets := make([]string, len(updates))
+	for i, name := range updates {
+		sets[i] = fmt.Sprintf("%s = values(%s)", name, name)
+	}
+	return fmt.Sprintf("insert into %s(%s)\nvalues (%s)\non duplicate key update %s;", table, strings.Join(names, ", "), placeholders(len(names)), strings.Join(sets, ", "))
+}
+
+// upsert_insert inserts the record of model writing its generated key back, like an upsert only the record table is
+// written
+func upsert_insert(model any, tablename string, m *MORM) Result {
+	v := pullvalue(model)
+	if !v.CanAddr() {
+		copy := reflect.New(v.Type()).Elem()
+		copy.Set(v)
+		v = copy
+	}
+
+	query, _, e := insertquery(v.Interface(), tablename)
+	if e != nil {
+		return error_result(err_wrap(e, fmt.Sprintf("unable to compose the insert of %s", v.Type().Name())))
+	}
+
+	tx, e := begin_tx(m)
+	if e != nil {
+		return error_result(e)
+	}
+
+	e = exec_insert(tx, query, v, m)
+	if e != nil {
+		tx.Rollback()
+		return error_result(err_wrap(e, fmt.Sprintf("unable to insert %s", v.Type().Name())))
+	}
+
+	if e := tx.Commit(); e != nil {
+		return error_result(e)
+	}
+
+	return new_result(nil, 1)
+}
@@ -1,14 +1,13 @@
 package morm
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
 	"time"
-
-	. "github.com/chapgx/assert/v2"
 )
 
 // toint turns any integer type to int
@@ -77,7 +76,11 @@ func anytostr(val any) (string, error) {
 		return "null", nil
 	}
 
-	t := pulltype(val)
+	t := reflect.TypeOf(val)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
 	var stringval string
 
 	switch t.Kind() {
@@ -95,12 +98,15 @@ func anytostr(val any) (string, error) {
 		}
 		sv := strconv.Itoa(int(v))
 		stringval = sv
+	case reflect.Float32, reflect.Float64:
+		fv := reflect.ValueOf(val).Float()
+		stringval = strconv.FormatFloat(fv, 'f', -1, 64)
 	case reflect.String:
 		sv, ok := val.(string)
 		if !ok {
 			return "", fmt.Errorf("%v unable to convert to string", val)
 		}
-		stringval = fmt.Sprintf("'%s'", sv)
+		stringval = fmt.Sprintf("'%s'", strings.ReplaceAll(sv, "'", "''"))
 	case reflect.Bool:
 		boolrlst, ok := val.(bool)
 		if !ok {
@@ -111,19 +117,36 @@ func anytostr(val any) (string, error) {
 		} else {
 			stringval = "0"
 		}
+	case reflect.Slice, reflect.Array:
+		// note: containers are rendered as a list for the IN comparison
+		v := reflect.ValueOf(val)
+		if v.Len() == 0 {
+			stringval = "(null)"
+			break
+		}
+
+		items := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			item, e := anytostr(v.Index(i).Interface())
+			if e != nil {
+				return "", e
+			}
+			items = append(items, item)
+		}
+		stringval = fmt.Sprintf("(%s)", strings.Join(items, ", "))
 	case reflect.Struct:
 		if istimetype(t) {
 			tval, ok := val.(time.Time)
 			if !ok {
 				return "", fmt.Errorf("%v unable to convert to time.Time", tval)
 			}
-			stringval = tval.Format(time.RFC3339)
+			stringval = fmt.Sprintf("'%s'", tval.Format(time.DateTime))
 			break
 		}
 
-		return "", fmt.Errorf("%v struct type is not supported", val)
+		return "", fmt.Errorf("%w: %v struct", ErrUnsupportedType, val)
 	default:
-		return "", fmt.Errorf("%v kind is not supported", val)
+		return "", fmt.Errorf("%w: %v kind", ErrUnsupportedType, val)
 	}
 
 	return stringval, nil
@@ -135,39 +158,40 @@ func istimetype(t reflect.Type) bool {
 }
 
 // pull_fields_and_values returns fiels and values from a struct
-func pull_fields_and_values(model any) (fields []string, values []string) {
-	t := pulltype(model)
-	v := reflect.ValueOf(model)
-
-	_, ok := model.(reflect.Type)
-	if ok {
-		fmt.Println("it is a type of reflect.Type")
+func pull_fields_and_values(model any) (fields []string, values []any, e error) {
+	t, e := pulltype(model)
+	if e != nil {
+		return nil, nil, e
 	}
 
+	v := reflect.ValueOf(model)
 	if v.Kind() == reflect.Pointer {
-		fmt.Println("is pointer seeting elem")
 		v = v.Elem()
-		fmt.Printf("%+v\n", v)
 	}
 
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
-		mormtag := gettag(field)
+		mormtag, e := gettag(field)
+		if e != nil {
+			return nil, nil, field_error(t, field, e)
+		}
 
 		// no tag branch
 		if mormtag.IsEmpty() {
 
-			if field.Type.Kind() == reflect.Struct {
-				// TODO: figure out how to handle nested structures
-				// NOTE: ignoring this for now
+			// note: adjecent tables are not created for flattened structures so their fields are not part of the record
+			switch field.Type.Kind() {
+			case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map:
 				continue
 			}
 
 			fieldname := fmt.Sprintf("%s_%s", strings.ToLower(t.Name()), strings.ToLower(field.Name))
 			fieldname = seen_before(fieldname, t.Name())
 
-			fieldval, e := tostring(v.Field(i), field.Type, mormtag)
-			Assert(e == nil, e)
+			fieldval, e := field_arg(v.Field(i), field.Type, mormtag)
+			if e != nil {
+				return nil, nil, field_error(t, field, e)
+			}
 
 			fields = append(fields, fieldname)
 			values = append(values, fieldval)
@@ -180,10 +204,12 @@ func pull_fields_and_values(model any) (fields []string, values []string) {
 			case IgnoreDirective:
 				continue
 			case FlattenDirective:
-				if t.Kind() == reflect.Struct {
-					// TODO: figure out how to handle nested structures directives
-					continue
+				nestedfields, nestedvalues, e := pull_fields_and_values(v.Field(i).Interface())
+				if e != nil {
+					return nil, nil, e
 				}
+				fields = append(fields, nestedfields...)
+				values = append(values, nestedvalues...)
 				continue
 			}
 		}
@@ -191,50 +217,269 @@ func pull_fields_and_values(model any) (fields []string, values []string) {
 		mormtag.SetFieldName(fmt.Sprintf("%s_%s", strings.ToLower(t.Name()), mormtag.fieldname))
 		mormtag.SetFieldName(seen_before(mormtag.fieldname, t.Name()))
 
-		fieldvalue, e := tostring(v.Field(i), field.Type, mormtag)
-		Assert(e == nil, e)
+		fieldvalue, e := field_arg(v.Field(i), field.Type, mormtag)
+		if e != nil {
+			return nil, nil, field_error(t, field, e)
+		}
 
 		fields = append(fields, mormtag.fieldname)
 		values = append(values, fieldvalue)
 	}
 
-	return fields, values
+	return fields, values, nil
 }
 
-func delete(tablename string, filters *Filter, m *MORM) Result {
-	if filters == nil {
-		return error_result(errors.New("filters are <nil>"))
+// update makes changes to the fields of the model in tablename, the model table when empty
+func update(model any, tablename string, filters *Filter, m *MORM, fields ...string) Result {
+	queries, e := update_queries(model, tablename, filters, fields...)
+	if e != nil {
+		return error_result(e)
+	}
+
+	if len(queries) == 1 {
+		return exec_write(m, queries[0].statement)
 	}
 
-	wheresql, e := filters.WhereSQL()
+	tx, e := begin_tx(m)
 	if e != nil {
 		return error_result(e)
 	}
 
-	query := fmt.Sprintf("delete from %s\n%s", tablename, wheresql)
-	_queryHistory = append(_queryHistory, query)
+	rslt := exec_tx(tx, queries, m.engine)
+	if rslt.Error != nil {
+		tx.Rollback()
+		return rslt
+	}
+
+	if e := tx.Commit(); e != nil {
+		return error_result(e)
+	}
+
+	return rslt
+}
+
+// table_statement is a statement writing to tablename
+type table_statement struct {
+	statement
+	tablename string
+}
+
+// exec_tx executes the queries in order on tx and returns the rows affected combined and by table, the caller
+// rolls back on error
+func exec_tx(tx *sql.Tx, queries []table_statement, engine ENGINE) Result {
+	rslt := Result{Tables: make(map[string]int64, len(queries))}
+	for _, query := range queries {
+		sqlr, e := query.exec(tx, engine)
+		if e != nil {
+			return error_result(e)
+		}
+
+		n, e := sqlr.RowsAffected()
+		if e != nil {
+			return error_result(e)
+		}
+
+		rslt.Tables[query.tablename] += n
+		rslt.RowsAffected += n
+	}
+
+	return rslt
+}
+
+// update_queries composes the update of the record table followed by the updates of the adjecent tables
+func update_queries(model any, tablename string, filters *Filter, fields ...string) ([]table_statement, error) {
+	t, e := pulltype(model)
+	if e != nil {
+		return nil, e
+	}
+	v := pullvalue(model)
+
+	if tablename == "" {
+		tablename = default_tablename(t)
+	}
+
+	var wsql string
+	var wargs []any
+	if filters != nil {
+		var e error
+		wsql, wargs, e = filters.WhereArgs()
+		if e != nil {
+			return nil, e
+		}
+	}
+
+	// note: fields of adjecent structs are updated in their own table, grouped by adjecent field
+	var sets []string
+	var args []any
+	var adjecent []string
+	adjecentsets := make(map[string]*statement)
+	for _, field := range fields {
+		head, rest, nested := strings.Cut(field, ".")
+		f, ok := t.FieldByName(head)
+		if tag, _ := gettag(f); nested && ok && tag.IsEmpty() && f.Type.Kind() == reflect.Struct {
+			set, arg, e := update_set(f.Type, v.FieldByIndex(f.Index), rest)
+			if e != nil {
+				return nil, e
+			}
+
+			if _, found := adjecentsets[head]; !found {
+				adjecent = append(adjecent, head)
+				adjecentsets[head] = &statement{}
+			}
+
+			// note: the sets of adjecent tables are joined with a comma once composed
+			adjecentset := adjecentsets[head]
+			if adjecentset.query != "" {
+				adjecentset.query += ","
+			}
+			adjecentset.query += set
+			adjecentset.args = append(adjecentset.args, arg)
+			continue
+		}
+
+		set, arg, e := update_set(t, v, field)
+		if e != nil {
+			return nil, e
+		}
+		sets = append(sets, set)
+		args = append(args, arg)
+	}
+
+	var queries []table_statement
+	if len(sets) > 0 {
+		query := fmt.Sprintf("update %s\nset %s", tablename, strings.Join(sets, ","))
+		if wsql != "" {
+			query += "\n" + wsql
+		}
+		queries = append(queries, table_statement{statement{query: query + ";", args: append(args, wargs...)}, tablename})
+	}
+
+	if len(adjecent) > 0 {
+		pk, ok := primary_key(t)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s adjecent fields can not be updated", ErrNoPrimaryKey, t.Name())
+		}
+
+		for _, head := range adjecent {
+			f, _ := t.FieldByName(head)
+			adjecentset := adjecentsets[head]
+			adjecenttable := default_tablename(f.Type)
+			query := fmt.Sprintf("update %s\nset %s", adjecenttable, adjecentset.query)
+			if wsql != "" {
+				query += fmt.Sprintf("\nwhere %s in (select %s from %s\n%s)", link_name(t, pk), pk.name, tablename, wsql)
+			}
+			queries = append(queries, table_statement{statement{query: query + ";", args: append(adjecentset.args, wargs...)}, adjecenttable})
+		}
+	}
+
+	if len(queries) == 0 {
+		return nil, errors.New("no fields to update")
+	}
+
+	return queries, nil
+}
+
+// update_set returns the column assignment of the field at path in v of type t and the value bound to it.
+//
+// path is a Go field name or a dotted path into flattened structs, for example Phone.Number
+func update_set(t reflect.Type, v reflect.Value, path string) (string, any, error) {
+	columns, e := model_columns(t)
+	if e != nil {
+		return "", nil, e
+	}
+
+	for _, c := range columns {
+		if column_path(t, c.index) != path {
+			continue
+		}
+
+		value, e := field_arg(v.FieldByIndex(c.index), c.field.Type, c.tag)
+		if e != nil {
+			return "", nil, err_wrap(e, fmt.Sprintf("unable to bind %s", path))
+		}
+
+		return c.name + "=?", value, nil
+	}
+
+	return "", nil, fmt.Errorf("%w: %s field not found in %s", ErrUnmappedField, path, t.Name())
+}
+
+// column_path returns the dotted Go field path of the field at index in t
+func column_path(t reflect.Type, index []int) string {
+	names := make([]string, 0, len(index))
+	for _, i := range index {
+		field := t.Field(i)
+		names = append(names, field.Name)
+		t = field.Type
+	}
+	return strings.Join(names, ".")
+}
+
+// delete_records deletes the records of tablename matching filters, filters are required
+func delete_records(tablename string, filters *Filter, m *MORM) Result {
+	query, e := delete_query(tablename, filters)
+	if e != nil {
+		return error_result(e)
+	}
+
+	return exec_write(m, query)
+}
+
+// delete_query composes the delete of the records matching filters, filters are required
+func delete_query(tablename string, filters *Filter) (statement, error) {
+	if filters == nil {
+		return statement{}, errors.New("filters are <nil>")
+	}
+
+	wheresql, args, e := filters.WhereArgs()
+	if e != nil {
+		return statement{}, e
+	}
+
+	return statement{query: fmt.Sprintf("delete from %s\n%s", tablename, wheresql), args: args}, nil
+}
+
+// exec_write executes a write query and returns the rows affected, on SQL Server the database context is set
+func exec_write(m *MORM, query statement) Result {
+	if m == nil {
+		return error_result(ErrDefaultClientIsNil)
+	}
 
 	if !m.connected {
 		e := m.connect()
-		Assert(e == nil, e)
+		if e != nil {
+			return error_result(e)
+		}
+	}
+
+	if m.engine == SQLServer {
+		usedb, e := mssql_use_db(m)
+		if e != nil {
+			return error_result(e)
+		}
+		query.query = usedb + query.query
 	}
 
-	sqlr, e := m.db.Exec(query)
+	rslt, e := query.exec(m.db, m.engine)
 	if e != nil {
 		return error_result(e)
 	}
 
-	affected, e := sqlr.RowsAffected()
+	affected, e := rslt.RowsAffected()
 	return new_result(e, affected)
 }
 
 func drop(tblname string, m *MORM) error {
 	//TODO: next drop table functionality
-	Assert(m != nil, "morm instance has not been initialized")
+	if m == nil {
+		return ErrDefaultClientIsNil
+	}
 
 	if !m.connected {
 		e := m.connect()
-		return e
+		if e != nil {
+			return e
+		}
 	}
 
 	_, e := m.db.Exec("drop table " + tblname + ";")
@@ -242,104 +487,264 @@ func drop(tblname string, m *MORM) error {
 	return e
 }
 
-func select_query(model reflect.Type, filters *Filter, m *MORM, tablename string, is_container bool) (string, error) {
-
-	var limited_selection string
-	if !is_container {
-		switch m.engine {
-		case SQLITE:
-			limited_selection = "LIMIT 1"
-		case SQLServer:
-			limited_selection = "top(1)"
-		}
+// select_query composes the select query of the model and returns the selected columns in order.
+//
+// When opts has a projection source the columns of model are mapped to the columns of the source model
+// and the table name is derived from the source
+func select_query(model reflect.Type, filters *Filter, m *MORM, tablename string, is_container bool, opts read_options) (statement, []column, error) {
+	columns, e := model_columns(model)
+	if e != nil {
+		return statement{}, nil, e
 	}
 
-	var selected_fields []string
-	for i := 0; i < model.NumField(); i += 1 {
-		field := model.Field(i)
-		tag := gettag(field)
-
-		if tag.IsEmpty() {
-			if field.Type.Kind() == reflect.Struct {
-				continue
-			}
-
-			selected_fields = append(selected_fields, strings.ToLower(field.Name))
-			continue
-		}
+	source := model
+	if opts.source != nil {
+		source = opts.source
 
-		if tag.IsDirective() {
-			switch tag.tag {
-			case IgnoreDirective:
-				continue
-			case FlattenDirective:
-				//TODO: we need to cash this so we can run it later and add it to the data itself. Or we ignore it and let the data addition add it
-				continue
-			}
+		columns, e = project_columns(model, source)
+		if e != nil {
+			return statement{}, nil, e
 		}
+	}
 
-		tag.fieldname = safe_keyword(tag.fieldname)
-		selected_fields = append(selected_fields, tag.fieldname)
-
+	if len(columns) == 0 {
+		return statement{}, nil, fmt.Errorf("%s has no columns to select", model.Name())
 	}
+	selected_fields := column_names(columns)
 
 	if tablename == "" {
-		tablename = model.Name()
+		tablename = default_tablename(source)
 	}
 
 	var where_clause string
+	var args []any
 	if filters != nil {
-		q, e := filters.WhereSQL()
+		q, wargs, e := filters.WhereArgs()
 		if e != nil {
-			return "", e
+			return statement{}, nil, e
 		}
-		where_clause = q
+		where_clause, args = q, wargs
+	}
+
+	// note: single record reads are always limited to one row
+	limit, limited := opts.limit, opts.limited
+	if !is_container {
+		limit, limited = 1, true
+	}
+
+	if limit < 0 || opts.offset < 0 {
+		return statement{}, nil, fmt.Errorf("limit and offset can not be negative")
+	}
+
+	orderby, e := order_by(opts.orderby, source)
+	if e != nil {
+		return statement{}, nil, e
 	}
 
 	var query string
 	switch m.engine {
 	case SQLITE:
+		query = fmt.Sprintf("select %s\nfrom %s", strings.Join(selected_fields, ", "), tablename)
 		if where_clause != "" {
-			query = fmt.Sprintf("select %s\nfrom %s\n%s\n%s;", strings.Join(selected_fields, ", "), tablename, where_clause, limited_selection)
-			break
+			query += "\n" + where_clause
+		}
+
+		if len(orderby) > 0 {
+			query += "\norder by " + strings.Join(orderby, ", ")
+		}
+
+		if limited {
+			query += fmt.Sprintf("\nLIMIT %d", limit)
+		} else if opts.offset > 0 {
+			query += "\nLIMIT -1"
+		}
+
+		if opts.offset > 0 {
+			query += fmt.Sprintf(" OFFSET %d", opts.offset)
 		}
-		query = fmt.Sprintf("select %s\nfrom %s\n%s;", strings.Join(selected_fields, ", "), tablename, limited_selection)
 	case SQLServer:
+		// note: top is only used when there is no offset, offset fetch requires an order by clause
+		var limited_selection string
+		if limited && opts.offset == 0 {
+			limited_selection = fmt.Sprintf("top(%d) ", limit)
+		}
+
+		query = fmt.Sprintf("select %s%s\nfrom %s", limited_selection, strings.Join(selected_fields, ", "), tablename)
 		if where_clause != "" {
-			query = fmt.Sprintf("select %s %s\nfrom %s\n%s;", limited_selection, strings.Join(selected_fields, ", "), tablename, where_clause)
-			break
+			query += "\n" + where_clause
+		}
+
+		if opts.offset > 0 && len(orderby) == 0 {
+			orderby = []string{"(select null)"}
+		}
+
+		if len(orderby) > 0 {
+			query += "\norder by " + strings.Join(orderby, ", ")
+		}
+
+		if opts.offset > 0 {
+			query += fmt.Sprintf("\noffset %d rows", opts.offset)
+			if limited {
+				query += fmt.Sprintf(" fetch next %d rows only", limit)
+			}
+		}
+	default:
+		return statement{}, nil, fmt.Errorf("%w: %s reads", ErrUnsupportedEngine, m.engine)
+	}
+
+	query += ";"
+
+	return statement{query: query, args: args}, columns, nil
+}
+
+// select_rows executes the select query of the model and returns the rows along the selected columns in order.
+//
+// The caller is responsible of closing the rows
+func select_rows(model reflect.Type, filters *Filter, m *MORM, tablename string, is_container bool, opts read_options) (*sql.Rows, []column, error) {
+	query, columns, e := select_query(model, filters, m, tablename, is_container, opts)
+	if e != nil {
+		return nil, nil, e
+	}
+
+	if !m.connected {
+		e := m.connect()
+		if e != nil {
+			return nil, nil, e
+		}
+	}
+
+	switch m.engine {
+	case SQLServer:
+		usedb, e := mssql_use_db(m)
+		if e != nil {
+			return nil, nil, e
 		}
-		query = fmt.Sprintf("select %s %s\nfrom %s;", limited_selection, strings.Join(selected_fields, ", "), tablename)
+		query.query = usedb + query.query
+	}
+
+	rows, e := query.query_rows(m.db, m.engine)
+	if e != nil {
+		return nil, nil, e
 	}
 
-	return query, nil
+	return rows, columns, nil
 }
 
-func read(model any, filters *Filter, m *MORM, tablename string) error {
+// read executes a select query and scans the result into model.
+//
+// model must be a pointer to a struct, in which case the first row is read and [ErrNotFound] is returned
+// if there is none, or a pointer to a slice of structs (or struct pointers) where every row is read.
+func read(model any, filters *Filter, m *MORM, tablename string, opts read_options) error {
+	if m == nil {
+		return ErrDefaultClientIsNil
+	}
+
+	v := reflect.ValueOf(model)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return ErrReadTargetNotPointer
+	}
+	v = v.Elem()
+
+	t := v.Type()
 	is_container := false
-	t := pulltype(model)
-	// v := pullvalue(model)
+	elem_is_pointer := false
 
-	if t.Kind() == reflect.Pointer {
+	if t.Kind() == reflect.Slice {
+		is_container = true
 		t = t.Elem()
+		if t.Kind() == reflect.Pointer {
+			elem_is_pointer = true
+			t = t.Elem()
+		}
 	}
 
-	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
-		t = t.Elem()
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: unable to read into %s, expected a struct or a slice of structs", ErrUnsupportedType, v.Type())
 	}
 
-	query, e := select_query(t, filters, m, tablename, is_container)
+	rows, columns, e := select_rows(t, filters, m, tablename, is_container, opts)
 	if e != nil {
 		return e
 	}
+	defer rows.Close()
 
-	//TODO: HIGH PRIORITY! finish logic
+	if !is_container {
+		if !rows.Next() {
+			if e := rows.Err(); e != nil {
+				return e
+			}
+			return ErrNotFound
+		}
 
-	fmt.Println("from engine", m.engine)
-	fmt.Println(query)
-	fmt.Println()
+		e := scan_row(rows, v, columns)
+		if e != nil {
+			return e
+		}
 
-	return nil
+		if e := rows.Err(); e != nil {
+			return e
+		}
+		rows.Close()
+
+		if opts.tracked {
+			e := m.track([]reflect.Value{v}, t, tablename, columns, opts)
+			if e != nil {
+				return e
+			}
+		}
+
+		return load_relations([]reflect.Value{v}, t, opts.relations, m)
+	}
+
+	records := reflect.MakeSlice(v.Type(), 0, 0)
+	for rows.Next() {
+		record := reflect.New(t)
+		e := scan_row(rows, record.Elem(), columns)
+		if e != nil {
+			return e
+		}
+
+		if elem_is_pointer {
+			records = reflect.Append(records, record)
+		} else {
+			records = reflect.Append(records, record.Elem())
+		}
+	}
+
+	if e := rows.Err(); e != nil {
+		return e
+	}
+	rows.Close()
+
+	v.Set(records)
+
+	parents := make([]reflect.Value, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		parent := v.Index(i)
+		if elem_is_pointer {
+			parent = parent.Elem()
+		}
+		parents = append(parents, parent)
+	}
+
+	if opts.tracked {
+		e := m.track(parents, t, tablename, columns, opts)
+		if e != nil {
+			return e
+		}
+	}
+
+	return load_relations(parents, t, opts.relations, m)
+}
+
+// default_tablename returns the table name used for a model when none is explicit.
+// The lowercase struct name pluralized with an s
+func default_tablename(t reflect.Type) string {
+	tablename := strings.ToLower(t.Name())
+	if !strings.HasSuffix(tablename, "s") {
+		tablename += "s"
+	}
+	return tablename
 }
 
 // seen_before checks if the fieldname being added to the query has been seen before and it alters the fieldname
@@ -365,16 +770,19 @@ func tostring(val reflect.Value, fieldType reflect.Type, tag MormTag) (string, e
 	case reflect.String:
 		iv, ok := inter.(string)
 		if !ok {
-			fmt.Printf("%+v\n", val)
 			return "", ErrValIsNotExpectedType
 		}
-		rval = "'" + iv + "'"
+		rval = "'" + strings.ReplaceAll(iv, "'", "''") + "'"
 	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int:
-		iv, ok := inter.(int)
-		if !ok {
+		if !val.CanInt() {
+			return "", ErrValIsNotExpectedType
+		}
+		rval = strconv.FormatInt(val.Int(), 10)
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint:
+		if !val.CanUint() {
 			return "", ErrValIsNotExpectedType
 		}
-		rval = strconv.Itoa(iv)
+		rval = strconv.FormatUint(val.Uint(), 10)
 	case reflect.Float32:
 		iv, ok := inter.(float32)
 		if !ok {
@@ -386,7 +794,7 @@ func tostring(val reflect.Value, fieldType reflect.Type, tag MormTag) (string, e
 		if !ok {
 			return "", ErrValIsNotExpectedType
 		}
-		rval = strconv.FormatFloat(iv, 'f', -1, 32)
+		rval = strconv.FormatFloat(iv, 'f', -1, 64)
 	case reflect.Bool:
 		iv, ok := inter.(bool)
 		if !ok {
@@ -422,7 +830,6 @@ func tostring(val reflect.Value, fieldType reflect.Type, tag MormTag) (string, e
 				rval = strconv.Itoa(int(n))
 			case "real":
 				t = t.UTC()
-				const unixEpochJD = 2440587.7
 				n := unixEpochJD + float64(t.UnixNano())/86400e9
 				rval = strconv.FormatFloat(n, 'f', -1, 64)
 			case "text", "date", "datetime":
@@ -434,7 +841,7 @@ func tostring(val reflect.Value, fieldType reflect.Type, tag MormTag) (string, e
 			return rval, nil
 		}
 
-		return "", fmt.Errorf("attempted to convert to string an unsuported type %s", fieldType.Kind().String())
+		return "", fmt.Errorf("%w: unable to convert %s to string", ErrUnsupportedType, fieldType.Kind().String())
 	default:
 		return "", errors.New("interface type has not tostring convertion available")
 	}
@@ -442,26 +849,39 @@ func tostring(val reflect.Value, fieldType reflect.Type, tag MormTag) (string, e
 	return rval, nil
 }
 
-// insert_adjecent composes the insert query for a nester struct adjecent to it's parent struct
-func insert_adjecent(model any, seenfields map[string]bool) []string {
+// insert_adjecent composes the insert query for a nester struct adjecent to it's parent struct and returns the
+// indexes of its own adjecent struct fields.
+//
+// If link is not nil the parent primary key is inserted into the link column
+func insert_adjecent(model any, seenfields map[string]bool, link *adjecent_link) (statement, []int, error) {
 	if seenfields == nil {
 		seenfields = make(map[string]bool)
 	}
 
-	t := pulltype(model)
+	t, e := pulltype(model)
+	if e != nil {
+		return statement{}, nil, e
+	}
 	v := pullvalue(model)
 
-	var queries []string
+	var adjecent []int
 	var insertfields []string
-	var insertvalues []string
+	var insertvalues []any
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
-		mormtag := gettag(field)
+		mormtag, e := gettag(field)
+		if e != nil {
+			return statement{}, nil, field_error(t, field, e)
+		}
 
 		if mormtag.IsEmpty() {
-			fname, fval, qrs := emptytagprocess(field, v, t, i, seenfields)
-			if fname == "" && fval == "" {
-				queries = append(queries, qrs...)
+			fname, fval, isadjecent, e := emptytagprocess(field, v, t, i, seenfields)
+			if e != nil {
+				return statement{}, nil, e
+			}
+
+			if isadjecent {
+				adjecent = append(adjecent, i)
 				continue
 			}
 
@@ -475,18 +895,19 @@ func insert_adjecent(model any, seenfields map[string]bool) []string {
 			case IgnoreDirective:
 				continue
 			case FlattenDirective:
-				if field.Type.Kind() == reflect.Struct {
-					interfa := v.Field(i).Interface()
-					fields, values := pull_fields_and_values(interfa)
-					insertfields = append(insertfields, fields...)
-					insertvalues = append(insertvalues, values...)
+				interfa := v.Field(i).Interface()
+				fields, values, e := pull_fields_and_values(interfa)
+				if e != nil {
+					return statement{}, nil, e
 				}
+				insertfields = append(insertfields, fields...)
+				insertvalues = append(insertvalues, values...)
 				continue
 			}
 		}
 
-		//HACK: this is a hack for now I will need a better solution in the future
-		if strings.Contains(mormtag.tag, "autoincrement") {
+		// note: generated columns are left to the database
+		if is_generated(mormtag) {
 			continue
 		}
 
@@ -498,32 +919,50 @@ func insert_adjecent(model any, seenfields map[string]bool) []string {
 
 		mormtag.SetFieldName(safe_keyword(mormtag.fieldname))
 
+		value, e := field_arg(v.Field(i), field.Type, mormtag)
+		if e != nil {
+			return statement{}, nil, field_error(t, field, e)
+		}
 		insertfields = append(insertfields, mormtag.fieldname)
-		value, e := tostring(v.Field(i), field.Type, mormtag)
-		Assert(e == nil, e)
 		insertvalues = append(insertvalues, value)
 	}
 
-	insertline := fmt.Sprintf("insert into %ss(%s)\nvalues (%s)", t.Name(), strings.Join(insertfields, ", "), strings.Join(insertvalues, ", "))
-	queries = append(queries, insertline)
+	if link != nil {
+		insertfields = append(insertfields, link.name)
+		insertvalues = append(insertvalues, link.value)
+	}
 
-	return queries
+	return insert_statement(default_tablename(t), insertfields, insertvalues), adjecent, nil
 }
 
 func extract_columns(model any, m *MORM) ([]string, error) {
-	t := pulltype(model)
+	t, e := pulltype(model)
+	if e != nil {
+		return nil, e
+	}
 
 	var columns []string
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
-		mormtag := gettag(field)
+		mormtag, e := gettag(field)
+		if e != nil {
+			return nil, field_error(t, field, e)
+		}
 
 		// note: untagged are added as text with their field name
 		if mormtag.IsEmpty() {
+			// note: adjecent tables are not created for flattened structures
+			if field.Type.Kind() == reflect.Struct {
+				continue
+			}
+
 			fieldname := fmt.Sprintf("%s_%s", strings.ToLower(t.Name()), strings.ToLower(field.Name))
 			fieldname = seen_before(fieldname, t.Name())
 			//TODO: need to validate using t.Name() is the correct action here
-			column := notag_column(field, fieldname, m, t.Name())
+			column, e := notag_column(field, fieldname, m, t.Name())
+			if e != nil {
+				return nil, field_error(t, field, e)
+			}
 			if column.FieldType == COLUMN {
 				columns = append(columns, column.query)
 			}
@@ -537,30 +976,27 @@ func extract_columns(model any, m *MORM) ([]string, error) {
 			case IgnoreDirective:
 				continue
 			case FlattenDirective:
-				if field.Type.Kind() != reflect.Struct {
-					break
-				}
 				cols, e := extract_columns(field.Type, m)
 				if e != nil {
 					return nil, e
 				}
 				columns = append(columns, cols...)
-				mormtag.tag = ""
+				continue
 			}
 		}
 
 		// note: checking if the field name has been seen in an upper structure and if it has not recorded
-		mormtag.SetFieldName(fmt.Sprintf("%s_%s", t.Name(), mormtag.fieldname))
+		mormtag.SetFieldName(fmt.Sprintf("%s_%s", strings.ToLower(t.Name()), mormtag.fieldname))
 		mormtag.SetFieldName(seen_before(mormtag.fieldname, t.Name()))
 
 		// TODO: for more complex types i will need to handle them differenly
 		switch field.Type.Kind() {
 		case reflect.Array:
-			columns = append(columns, mormtag.fieldname)
+			columns = append(columns, mormtag.tag)
 		case reflect.Map:
-			columns = append(columns, mormtag.fieldname)
+			columns = append(columns, mormtag.tag)
 		default:
-			columns = append(columns, mormtag.fieldname)
+			columns = append(columns, mormtag.tag)
 		}
 
 	}
@@ -568,19 +1004,32 @@ func extract_columns(model any, m *MORM) ([]string, error) {
 	return columns, nil
 }
 
-func pulltype(model any) reflect.Type {
-	var t reflect.Type
-	tt, ok := model.(reflect.Type)
-	if ok {
-		return tt
+// pulltype returns the struct type of model, a struct, a pointer to a struct or a struct [reflect.Type].
+// Anything else, nil included, is an [ErrUnsupportedType] error
+func pulltype(model any) (reflect.Type, error) {
+	t, ok := model.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(model)
+
+		v := reflect.ValueOf(model)
+		if v.Kind() == reflect.Pointer && v.IsNil() {
+			return nil, fmt.Errorf("%w: model is a <nil> %s", ErrUnsupportedType, t)
+		}
+	}
+
+	if t == nil {
+		return nil, fmt.Errorf("%w: model is <nil>", ErrUnsupportedType)
 	}
 
-	t = reflect.TypeOf(model)
 	if t.Kind() == reflect.Pointer {
-		return t.Elem()
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %s, expected a struct", ErrUnsupportedType, t)
 	}
 
-	return t
+	return t, nil
 }
 
 // pullvalue returns the reflect.Value of an any type
@@ -594,64 +1043,249 @@ func pullvalue(model any) reflect.Value {
 	return v
 }
 
+// insert inserts the record and its adjecent records in a single transaction.
+//
+// Generated primary keys are written back into model when it is a pointer, and adjecent records are linked with the
+// generated key of their parent
 func insert(model any, tblname string, m *MORM) error {
-	var queries []string
-	insert_queries := insertquery(model, true, tblname, m)
-	Assert(len(insert_queries) >= 1, "expected to have queries to process but found none")
+	v := reflect.ValueOf(model)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
 
-	switch m.engine {
-	case SQLServer:
-		usedb, e := mssql_use_db(m)
-		Assert(e == nil, e)
-		queries = append(queries, usedb[:len(usedb)-1])
-		queries = append(queries, insert_queries...)
-	case SQLITE:
-		queries = insert_queries
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: unable to insert %T, expected a struct", ErrUnsupportedType, model)
+	}
+
+	if !v.CanAddr() {
+		// note: a copy so generated keys can be passed down to the adjecent records
+		copy := reflect.New(v.Type()).Elem()
+		copy.Set(v)
+		v = copy
+	}
+
+	tx, e := begin_tx(m)
+	if e != nil {
+		return e
+	}
+
+	e = insert_record(tx, v, tblname, nil, m)
+	if e != nil {
+		tx.Rollback()
+		return e
+	}
+
+	return tx.Commit()
+}
+
+// begin_tx connects if needed and begins a transaction, on SQL Server the database context is set
+func begin_tx(m *MORM) (*sql.Tx, error) {
+	if m == nil {
+		return nil, ErrDefaultClientIsNil
 	}
 
 	if !m.connected {
-		m.connect()
+		e := m.connect()
+		if e != nil {
+			return nil, e
+		}
+	}
+
+	tx, e := m.db.Begin()
+	if e != nil {
+		return nil, e
+	}
+
+	if m.engine != SQLServer {
+		return tx, nil
+	}
+
+	usedb, e := mssql_use_db(m)
+	if e != nil {
+		tx.Rollback()
+		return nil, e
+	}
+
+	if usedb != "" {
+		_queryHistory = append(_queryHistory, usedb)
+		if _, e := tx.Exec(usedb); e != nil {
+			tx.Rollback()
+			return nil, e
+		}
+	}
+
+	return tx, nil
+}
+
+// insert_record inserts the record v, writes its generated key back and then inserts its adjecent records.
+// v must be addressable, link is nil for the top level record
+func insert_record(tx *sql.Tx, v reflect.Value, tablename string, link *adjecent_link, m *MORM) error {
+	t := v.Type()
+
+	var query statement
+	var adjecent []int
+	var e error
+	if link == nil {
+		query, adjecent, e = insertquery(v.Interface(), tablename)
+	} else {
+		query, adjecent, e = insert_adjecent(v.Interface(), nil, link)
+	}
+
+	if e != nil {
+		return err_wrap(e, fmt.Sprintf("unable to compose the insert of %s", t.Name()))
+	}
+
+	e = exec_insert(tx, query, v, m)
+	if e != nil {
+		return err_wrap(e, fmt.Sprintf("unable to insert %s", t.Name()))
 	}
 
-	query := strings.Join(queries, ";\n\n")
-	_queryHistory = append(_queryHistory, query)
+	if len(adjecent) == 0 {
+		return nil
+	}
 
-	_, e := m.db.Exec(query)
+	// note: the link is computed after the insert so it carries the generated key
+	childlink, e := new_adjecent_link(t, v)
 	if e != nil {
 		return e
 	}
 
-	return e
+	for _, i := range adjecent {
+		e := insert_record(tx, v.Field(i), "", childlink, m)
+		if e != nil {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// generated_key returns the primary key of t when the database generates it
+func generated_key(t reflect.Type) (column, bool) {
+	pk, ok := primary_key(t)
+	if !ok {
+		return column{}, false
+	}
+
+	if !is_generated(pk.tag) {
+		return column{}, false
+	}
+
+	return pk, true
+}
+
+// is_generated checks if the column value is generated by the database on insert
+func is_generated(tag MormTag) bool {
+	lower := strings.ToLower(tag.tag)
+	return strings.Contains(lower, "autoincrement") || strings.Contains(lower, "auto_increment") || strings.Contains(lower, "identity")
 }
 
-// insertquery composes an insert query
-func insertquery(model any, independentTable bool, tablename string, m *MORM) []string {
+// exec_insert executes the insert query and scans the generated key into v if the record has one.
+//
+// SQLITE uses RETURNING, SQL Server OUTPUT INSERTED and MySQL the last insert id
+func exec_insert(tx *sql.Tx, query statement, v reflect.Value, m *MORM) error {
+	pk, generated := generated_key(v.Type())
+	if !generated {
+		_, e := query.exec(tx, m.engine)
+		return e
+	}
+
+	dst := &field_scanner{dst: v.FieldByIndex(pk.index), tag: pk.tag}
+
+	switch m.engine {
+	case SQLITE:
+		query.query = fmt.Sprintf("%s\nreturning %s", query.query, pk.name)
+	case SQLServer:
+		// note: the output clause goes before values or default values
+		values := strings.LastIndex(query.query, "\nvalues (")
+		if values == -1 {
+			values = strings.LastIndex(query.query, " default values")
+		}
+		query.query = fmt.Sprintf("%s\noutput inserted.%s%s", query.query[:values], pk.name, query.query[values:])
+	default:
+		rslt, e := query.exec(tx, m.engine)
+		if e != nil {
+			return e
+		}
+
+		id, e := rslt.LastInsertId()
+		if e != nil {
+			return e
+		}
+		return dst.Scan(id)
+	}
+
+	return query.query_row(tx, m.engine).Scan(dst)
+}
+
+// insert_statement composes the insert of a single record
+func insert_statement(tablename string, fields []string, values []any) statement {
+	if len(fields) == 0 {
+		return statement{query: fmt.Sprintf("insert into %s default values", tablename)}
+	}
+
+	query := fmt.Sprintf("insert into %s(%s)\nvalues (%s)", tablename, strings.Join(fields, ", "), placeholders(len(values)))
+	return statement{query, values}
+}
+
+// insertquery composes the insert query of a record and returns the indexes of its adjecent struct fields
+func insertquery(model any, tablename string) (statement, []int, error) {
+	insertline, valuesline, adjecent, e := insertfields(model)
+	if e != nil {
+		return statement{}, nil, e
+	}
+	if tablename == "" {
+		t, _ := pulltype(model)
+		tablename = default_tablename(t)
+	}
+
+	return insert_statement(tablename, insertline, valuesline), adjecent, nil
+}
+
+// insertfields returns the columns and values of the record table and the indexes of the adjecent struct fields
+func insertfields(model any) ([]string, []any, []int, error) {
 	insertdepth++
+	defer func() {
+		if insertdepth <= 1 {
+			seen = make(map[string]struct{})
+		}
+		insertdepth--
+	}()
 
-	t := pulltype(model)
-	v := reflect.ValueOf(model)
+	t, e := pulltype(model)
+	if e != nil {
+		return nil, nil, nil, e
+	}
 
+	v := reflect.ValueOf(model)
 	if v.Kind() == reflect.Pointer {
 		v = v.Elem()
 	}
 
-	executionchain := make([]string, 0)
-
+	var adjecent []int
 	var insertline []string
-	var valuesline []string
+	var valuesline []any
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
-		mormtag := gettag(field)
+		mormtag, e := gettag(field)
+		if e != nil {
+			return nil, nil, nil, field_error(t, field, e)
+		}
 
 		// no tag branch
 		if mormtag.IsEmpty() {
 
-			fname, fval, q := emptytagprocess(field, v, t, i, nil)
-			if fname == "" && fval == "" {
-				executionchain = append(executionchain, q...)
+			fname, fval, isadjecent, e := emptytagprocess(field, v, t, i, nil)
+			if e != nil {
+				return nil, nil, nil, e
+			}
+
+			if isadjecent {
+				adjecent = append(adjecent, i)
 				continue
 			}
 
+			seen[fname] = struct{}{}
 			insertline = append(insertline, fname)
 			valuesline = append(valuesline, fval)
 			continue
@@ -663,45 +1297,33 @@ func insertquery(model any, independentTable bool, tablename string, m *MORM) []
 			case IgnoreDirective:
 				continue
 			case FlattenDirective:
-				if t.Kind() == reflect.Struct {
-					i := v.Field(i).Interface()
-					fields, values := pull_fields_and_values(i)
-					insertline = append(insertline, fields...)
-					valuesline = append(valuesline, values...)
+				i := v.Field(i).Interface()
+				fields, values, e := pull_fields_and_values(i)
+				if e != nil {
+					return nil, nil, nil, e
 				}
+				insertline = append(insertline, fields...)
+				valuesline = append(valuesline, values...)
 				continue
 			}
 		}
 
-		//HACK: this is a hack for now I will need a better solution in the future
-		if strings.Contains(mormtag.tag, "autoincrement") {
+		// note: generated columns are left to the database
+		if is_generated(mormtag) {
 			continue
 		}
 
 		mormtag.SetFieldName(safe_keyword(mormtag.fieldname))
 		mormtag.SetFieldName(seen_before(mormtag.fieldname, t.Name()))
 
-		fieldvalue, e := tostring(v.Field(i), field.Type, mormtag)
-		Assert(e == nil, e)
+		fieldvalue, e := field_arg(v.Field(i), field.Type, mormtag)
+		if e != nil {
+			return nil, nil, nil, field_error(t, field, e)
+		}
 
 		insertline = append(insertline, mormtag.fieldname)
 		valuesline = append(valuesline, fieldvalue)
 	}
 
-	if tablename == "" {
-		tablename = t.Name() + "s"
-	}
-
-	qi := fmt.Sprintf("insert into %s(%s)\n", tablename, strings.Join(insertline, ", "))
-	qv := fmt.Sprintf("values (%s)", strings.Join(valuesline, ", "))
-	qi += qv
-
-	executionchain = append(executionchain, qi)
-
-	if insertdepth <= 1 {
-		seen = make(map[string]struct{})
-	}
-
-	insertdepth--
-	return executionchain
+	return insertline, valuesline, adjecent, nil
 }